@@ -0,0 +1,84 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"math"
+)
+
+/*
+Window background, a texture stretched over the whole window drawn before any render passes each frame.
+Scrolling wraps the texture around so it appears to repeat.
+*/
+
+var (
+	backgroundTexture        *opengl.Texture
+	backgroundObj            *RenderObject
+	backgroundU, backgroundV float32
+)
+
+// SetBackgroundTexture ... draw t over the whole window behind everything each frame, pass nil to remove the background
+func SetBackgroundTexture(t *opengl.Texture) {
+	backgroundTexture = t
+
+	if t == nil {
+		deleteBackground()
+
+		return
+	}
+
+	if backgroundObj == nil {
+		backgroundObj = &RenderObject{}
+		// Enough for the four tiles visible while scrolling
+		createRenderObject(backgroundObj, 4*6, t.File(), true)
+		backgroundObj.screen = true
+	}
+
+	backgroundObj.SetTexture(t)
+}
+
+// SetBackgroundScroll ... offset the background by u, v in texture widths and heights, eg increase u each frame for
+// horizontal parallax scrolling. Defaults to 0, 0.
+func SetBackgroundScroll(u, v float32) {
+	backgroundU = u
+	backgroundV = v
+}
+
+func renderBackground() {
+	if backgroundTexture == nil {
+		return
+	}
+
+	// Only the fractional offset matters as the texture wraps
+	u := backgroundU - float32(math.Floor(float64(backgroundU)))
+	v := backgroundV - float32(math.Floor(float64(backgroundV)))
+
+	x := -u * windowWidth
+	y := -v * windowHeight
+	texWidth := float32(backgroundTexture.Width())
+	texHeight := float32(backgroundTexture.Height())
+
+	stage := backgroundObj.Stage()
+	stage.Reset()
+
+	for _, offset := range [][2]float32{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		stage.AddRect(
+			x+offset[0]*windowWidth, y+offset[1]*windowHeight,
+			0, 0,
+			windowWidth, windowHeight,
+			texWidth, texHeight,
+		)
+	}
+
+	backgroundObj.Commit(stage)
+
+	// Behind everything when depth testing
+	backgroundObj.SetDepth(depthFar)
+	backgroundObj.Render()
+}
+
+func deleteBackground() {
+	if backgroundObj != nil {
+		backgroundObj.Delete()
+		backgroundObj = nil
+	}
+}
@@ -0,0 +1,76 @@
+package graphics
+
+import (
+	"fmt"
+	"gopengl/graphics/opengl"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Baking to textures, complex static geometry is rendered once into a texture so it can be drawn as a single square.
+Each render object has one baked texture, baking again overwrites it so it can be re-baked when the source changes.
+*/
+
+// BakeToTexture ... render obj into a texture sized to its bounds with a transparent background, the texture's top
+// left is the top left of the bounds. Translation, camera and zoom are ignored so only the vertices are baked.
+// Must be called on the main thread.
+func (obj *RenderObject) BakeToTexture() (*opengl.Texture, error) {
+	x, y, boundsWidth, boundsHeight := obj.Bounds()
+	width := int(math.Ceil(float64(boundsWidth)))
+	height := int(math.Ceil(float64(boundsHeight)))
+
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("cannot bake a render object with empty bounds to a texture")
+	}
+
+	var maxSize int32
+	gl.GetIntegerv(gl.MAX_TEXTURE_SIZE, &maxSize)
+
+	if width > int(maxSize) || height > int(maxSize) {
+		return nil, fmt.Errorf("cannot bake a %dx%d render object, textures are limited to %dx%d", width, height, maxSize, maxSize)
+	}
+
+	name := fmt.Sprintf("gopengl:bake:%p", obj)
+	texture := opengl.FindTex(name)
+
+	// Re-baking at a new size needs a new texture
+	if texture != nil && (texture.Width() != width || texture.Height() != height) {
+		opengl.DeleteTexture(texture)
+		texture = nil
+	}
+
+	if texture == nil {
+		texture = opengl.CreateEmptyTexture(name, width, height)
+	}
+
+	yUp := obj.yUp()
+	screen := obj.screen
+	pointers := make([]*float32, ptrNum)
+	copy(pointers, obj.ptrVars)
+
+	withScreenSize(float32(width), float32(height), func() {
+		// With y up the top of the bounds is its largest y, flipping back keeps the texture upright
+		if yUp {
+			flipY = 1
+			updateProjection()
+		}
+
+		// Move the bounds to the top left of the texture
+		transX, transY := -x, -y
+		var camera, zoom float32 = 0, 1
+
+		obj.screen = true
+		obj.SetTranslate(&transX, &transY)
+		obj.SetCamera(&camera, &camera)
+		obj.SetZoom(&zoom)
+
+		renderToTexture(texture, obj.Render)
+
+		obj.screen = screen
+		copy(obj.ptrVars, pointers)
+	})
+
+	return texture, nil
+}
@@ -0,0 +1,52 @@
+package graphics
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Blending, a global blend mode is used for everything unless a render object overrides it
+*/
+
+type BlendMode int
+
+const (
+	BlendInherit  BlendMode = iota // Use the global blend mode, only valid for render objects
+	BlendNone                      // Fragments overwrite the framebuffer
+	BlendAlpha                     // Standard alpha blending
+	BlendAdditive                  // Fragments are added to the framebuffer, eg for glows
+	BlendMultiply                  // Fragments multiply the framebuffer, eg for shadows
+)
+
+var globalBlendMode = BlendNone
+
+// SetBlendMode ... set the blend mode used by everything without its own blend mode
+func SetBlendMode(mode BlendMode) {
+	if mode == BlendInherit {
+		mode = BlendNone
+	}
+
+	globalBlendMode = mode
+	applyBlendMode(mode)
+}
+
+// SetBlendMode ... override the global blend mode for this render object, BlendInherit restores the global mode
+func (obj *RenderObject) SetBlendMode(mode BlendMode) {
+	obj.blend = mode
+}
+
+func applyBlendMode(mode BlendMode) {
+	switch mode {
+	case BlendAlpha:
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	case BlendAdditive:
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE)
+	case BlendMultiply:
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.DST_COLOR, gl.ONE_MINUS_SRC_ALPHA)
+	default:
+		gl.Disable(gl.BLEND)
+	}
+}
@@ -0,0 +1,59 @@
+package graphics
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Simple 2d bodies, not a physics engine, just integration of movement decoupled from rendering with aabb overlap
+tests for basic collision. Integrating with a fixed dt keeps movement stable regardless of the frame rate.
+*/
+
+type Body2D struct {
+	Position     mgl32.Vec2 // Bounding box x, y in pixels from the coordinate origin
+	Velocity     mgl32.Vec2 // Pixels per second
+	Acceleration mgl32.Vec2 // Pixels per second per second
+	Size         mgl32.Vec2 // Width and height of the body's bounding box
+}
+
+// CreateBody2D ... a stationary body at x, y with a width by height bounding box
+func CreateBody2D(x, y, width, height float32) *Body2D {
+	return &Body2D{
+		Position: mgl32.Vec2{x, y},
+		Size:     mgl32.Vec2{width, height},
+	}
+}
+
+// Integrate ... advance the body by dt seconds using semi-implicit euler integration, velocity is updated before
+// position which is more stable than explicit euler for the same cost.
+func (b *Body2D) Integrate(dt float32) {
+	b.Velocity = b.Velocity.Add(b.Acceleration.Mul(dt))
+	b.Position = b.Position.Add(b.Velocity.Mul(dt))
+}
+
+// Attach ... translate obj by the body's position, the render object follows the body without further syncing.
+// Translations are in the same coordinates as vertices, so obj's geometry should be built with the body's x, y at 0, 0.
+func (b *Body2D) Attach(obj *RenderObject) {
+	obj.SetTranslate(&b.Position[0], &b.Position[1])
+}
+
+// Bounds ... the body's bounding box
+func (b *Body2D) Bounds() Rect {
+	return Rect{b.Position.X(), b.Position.Y(), b.Size.X(), b.Size.Y()}
+}
+
+// Overlaps ... whether the bounding boxes of the bodies overlap
+func (b *Body2D) Overlaps(other *Body2D) bool {
+	return b.Bounds().Overlaps(other.Bounds())
+}
+
+// Overlaps ... whether the rectangles overlap, rectangles only sharing an edge do not overlap
+func (r Rect) Overlaps(other Rect) bool {
+	return r.X < other.X+other.Width && other.X < r.X+r.Width &&
+		r.Y < other.Y+other.Height && other.Y < r.Y+r.Height
+}
+
+// Contains ... whether the point x, y is inside the rectangle
+func (r Rect) Contains(x, y float32) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
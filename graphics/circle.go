@@ -0,0 +1,69 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+)
+
+/*
+Smooth circles, drawn as a single square with the edge anti-aliased in the fragment shader so they are perfectly round
+at any size. The square's texture coordinates hold the position within the circle offset by circleTexOffset, marking
+it as a circle rather than a textured square. Requires the default shader.
+*/
+
+const circleTexOffset = 2
+
+// AddSmoothCircle ... add a circle of colour c centred on cx, cy. Returns the index of its first vertex
+func (obj *RenderObject) AddSmoothCircle(cx, cy, radius float32, c Color) int {
+	x, y := cx-radius, cy-radius
+	width := 2 * radius
+
+	verts := []float32{
+		// Upper right triangle
+		x, y,
+		x + width, y,
+		x + width, y + width,
+
+		// Lower left triangle
+		x, y,
+		x + width, y + width,
+		x, y + width,
+	}
+
+	texs := []float32{
+		// Upper right triangle
+		-1, -1,
+		1, -1,
+		1, 1,
+
+		// Lower left triangle
+		-1, -1,
+		1, 1,
+		-1, 1,
+	}
+
+	for i := range texs {
+		texs[i] -= circleTexOffset
+	}
+
+	index := obj.allocate(6)
+	obj.vao.UpdateBufferIndex(index, verts, texs)
+	obj.SetColor(index, 6, c)
+
+	return index
+}
+
+// isCircleTex ... whether a normalized texture x coordinate belongs to a smooth circle, the same test as the fragment
+// shader. Circle coordinates span -1-circleTexOffset to 1-circleTexOffset, all below -0.5.
+func isCircleTex(xTex float32) bool {
+	return xTex < 1.5-circleTexOffset
+}
+
+// keepCircleTexs ... restore the texture coordinates of circles in texs from original, which texs was converted from
+func keepCircleTexs(texs, original []float32) {
+	for i := 0; i < len(original); i += opengl.DEFAULT_TEXS_SIZE {
+		if isCircleTex(original[i]) {
+			texs[i] = original[i]
+			texs[i+1] = original[i+1]
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Stencil clipping, geometry drawn inside a clip is only visible where it overlaps the clip polygon.
+Clips can be nested, each nested clip only draws inside the intersection of itself and all of its parents.
+*/
+
+var clipDepth int32 = 0
+
+// ClipToPolygon ... run draw with all rendering clipped to the polygon, points are x, y pairs in pixels.
+// The polygon is drawn as a triangle fan so must be convex.
+func ClipToPolygon(points []float32, draw func()) {
+	if clipDepth == 0 {
+		gl.Enable(gl.STENCIL_TEST)
+	}
+
+	// Write the polygon into the stencil buffer, only incrementing inside the parent clip
+	writeClipStencil(points, gl.INCR)
+	clipDepth++
+
+	gl.StencilFunc(gl.EQUAL, clipDepth, 0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+
+	draw()
+
+	// Remove the polygon from the stencil buffer so the parent clip is restored
+	writeClipStencil(points, gl.DECR)
+	clipDepth--
+
+	if clipDepth == 0 {
+		gl.Disable(gl.STENCIL_TEST)
+
+		return
+	}
+
+	gl.StencilFunc(gl.EQUAL, clipDepth, 0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+}
+
+func writeClipStencil(points []float32, op uint32) {
+	gl.ColorMask(false, false, false, false)
+	gl.StencilFunc(gl.EQUAL, clipDepth, 0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, op)
+
+	opengl.DrawImmediate(gl.TRIANGLE_FAN, pointsToTopLeft(points), mgl32.Vec4{}, windowWidth, windowHeight)
+
+	gl.ColorMask(true, true, true, true)
+}
@@ -0,0 +1,30 @@
+package graphics
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Color ... RGBA colour, each component ranges from 0 to 1
+type Color struct {
+	R, G, B, A float32
+}
+
+var (
+	White       = Color{1, 1, 1, 1}
+	Black       = Color{0, 0, 0, 1}
+	Transparent = Color{0, 0, 0, 0}
+)
+
+func (c Color) vec4() mgl32.Vec4 {
+	return mgl32.Vec4{c.R, c.G, c.B, c.A}
+}
+
+// Mod ... the colour a texel of c is drawn as with SetColorMod(mul, add), matching the fragment shader
+func (c Color) Mod(mul, add Color) Color {
+	return Color{
+		c.R*mul.R + add.R,
+		c.G*mul.G + add.G,
+		c.B*mul.B + add.B,
+		c.A*mul.A + add.A,
+	}
+}
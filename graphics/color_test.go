@@ -0,0 +1,26 @@
+package graphics
+
+import "testing"
+
+func TestColorMod(t *testing.T) {
+	red := Color{1, 0, 0, 1}
+
+	tests := []struct {
+		name            string
+		texel, mul, add Color
+		want            Color
+	}{
+		{"default leaves the texel unchanged", Color{0.2, 0.4, 0.6, 0.8}, White, Transparent, Color{0.2, 0.4, 0.6, 0.8}},
+		{"mul tints", White, red, Transparent, red},
+		{"mul alpha fades", White, Color{1, 1, 1, 0.5}, Transparent, Color{1, 1, 1, 0.5}},
+		{"add flashes white", Color{0.5, 0, 0.25, 1}, White, Color{1, 1, 1, 0}, Color{1.5, 1, 1.25, 1}},
+		{"mul is applied before add", Color{0.5, 0.5, 0.5, 1}, Color{0.5, 0.5, 0.5, 1}, Color{0.25, 0, 0, 0}, Color{0.5, 0.25, 0.25, 1}},
+		{"black mul with add draws the add colour", Color{0.3, 0.7, 0.1, 1}, Black, Color{0, 1, 0, 0}, Color{0, 1, 0, 1}},
+	}
+
+	for _, test := range tests {
+		if got := test.texel.Mod(test.mul, test.add); got != test.want {
+			t.Errorf("%s: %v.Mod(%v, %v) = %v, want %v", test.name, test.texel, test.mul, test.add, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Debug drawing, shapes are queued and drawn over all render objects at the end of the next Render then discarded.
+Must be called on the main thread, for persistent shapes call them every frame.
+*/
+
+type debugLine struct {
+	verts  []float32
+	colour Color
+}
+
+type debugText struct {
+	text   string
+	x, y   float32
+	colour Color
+}
+
+const (
+	debugFontTexture = "gopengl:debugfont"
+	maxDebugGlyphs   = 2048
+)
+
+var (
+	debugLines    []debugLine
+	debugTexts    []debugText
+	debugTextObj  *RenderObject
+	debugFont     *Font
+	lineSmoothing = false
+	triangleEdges = false
+)
+
+// DrawLine ... queue a line from x1, y1 to x2, y2 in pixels for the next frame
+func DrawLine(x1, y1, x2, y2 float32, c Color) {
+	debugLines = append(debugLines, debugLine{pointsToTopLeft([]float32{x1, y1, x2, y2}), c})
+}
+
+// DrawDebugText ... queue text with its top left at x, y in pixels for the next frame using the built in 8x8 font.
+// Only printable ascii is supported, other runes are drawn as '?'.
+func DrawDebugText(s string, x, y float32, c Color) {
+	x, y = toTopLeft(x, y)
+	debugTexts = append(debugTexts, debugText{s, x, y, c})
+}
+
+// SetTriangleEdges ... enable DrawTriangleEdges, while disabled calls to it are ignored so they can be left in place
+func SetTriangleEdges(enabled bool) {
+	triangleEdges = enabled
+}
+
+// DrawTriangleEdges ... queue the outline of every triangle in obj for the next frame, the edge shared by a square's
+// two triangles is its diagonal. Vertices are read from the render object's vertex data before any transformations.
+// Only render objects drawn as gl.TRIANGLES are outlined.
+func DrawTriangleEdges(obj *RenderObject, c Color) {
+	if !triangleEdges || obj.mode != gl.TRIANGLES {
+		return
+	}
+
+	verts := obj.vao.Verts()[:obj.freeVert*opengl.DEFAULT_VECTOR_SIZE]
+	triangle := 3 * opengl.DEFAULT_VECTOR_SIZE
+	edges := make([]float32, 0, 2*len(verts))
+
+	for i := 0; i+triangle <= len(verts); i += triangle {
+		p0, p1, p2 := verts[i:i+2], verts[i+2:i+4], verts[i+4:i+6]
+		edges = append(edges,
+			p0[0], p0[1], p1[0], p1[1],
+			p1[0], p1[1], p2[0], p2[1],
+			p2[0], p2[1], p0[0], p0[1],
+		)
+	}
+
+	if len(edges) == 0 {
+		return
+	}
+
+	debugLines = append(debugLines, debugLine{pointsToTopLeft(edges), c})
+}
+
+// SetLineSmoothing ... anti-alias debug lines using GL_LINE_SMOOTH, blending is enabled while lines are drawn.
+// Support varies by driver, if unsupported lines are drawn aliased.
+func SetLineSmoothing(smooth bool) {
+	lineSmoothing = smooth
+}
+
+func renderDebug() {
+	renderDebugLines()
+	renderDebugText()
+}
+
+func renderDebugText() {
+	if len(debugTexts) == 0 {
+		return
+	}
+
+	if debugTextObj == nil {
+		opengl.TextureFromImage(debugFontTexture, debugFontImage())
+
+		debugTextObj = &RenderObject{}
+		createRenderObject(debugTextObj, maxDebugGlyphs*6, debugFontTexture, true)
+		debugTextObj.screen = true
+		debugFont = CreateBitmapFont(debugFontTexture, debugGlyphSize, debugGlyphSize, debugFontFirst, debugFontColumns)
+	}
+
+	// Stage every glyph so the frame's text is uploaded once
+	stage := debugTextObj.Stage()
+	stage.Reset()
+	glyphs := 0
+
+	for _, text := range debugTexts {
+		runes := []rune(text.text)
+
+		if glyphs+len(runes) > maxDebugGlyphs {
+			break
+		}
+
+		for i, r := range runes {
+			if r != '\n' && (r < debugFontFirst || int(r-debugFontFirst) >= len(debugFontGlyphs)) {
+				runes[i] = '?'
+			}
+		}
+
+		glyphs += len(runes)
+		stage.SetColor(text.colour)
+		stage.AddText(debugFont, string(runes), text.x, text.y, 1)
+	}
+
+	debugTextObj.Commit(stage)
+	debugTextObj.Render()
+	debugTexts = debugTexts[:0]
+}
+
+func deleteDebug() {
+	if debugTextObj != nil {
+		debugTextObj.Delete()
+		debugTextObj = nil
+	}
+}
+
+func renderDebugLines() {
+	if len(debugLines) == 0 {
+		return
+	}
+
+	smoothed := lineSmoothing && enableLineSmoothing()
+	blended := gl.IsEnabled(gl.BLEND)
+
+	if smoothed && !blended {
+		gl.Enable(gl.BLEND)
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	}
+
+	for _, line := range debugLines {
+		opengl.DrawImmediate(gl.LINES, line.verts, line.colour.vec4(), windowWidth, windowHeight)
+	}
+
+	if smoothed {
+		gl.Disable(gl.LINE_SMOOTH)
+
+		if !blended {
+			gl.Disable(gl.BLEND)
+		}
+	}
+
+	debugLines = debugLines[:0]
+}
+
+func enableLineSmoothing() bool {
+	// Errors left by earlier calls would otherwise be blamed on line smoothing
+	for gl.GetError() != gl.NO_ERROR {
+	}
+
+	gl.Enable(gl.LINE_SMOOTH)
+
+	if gl.GetError() != gl.NO_ERROR {
+		// Unsupported, stop trying every frame
+		lineSmoothing = false
+
+		return false
+	}
+
+	return true
+}
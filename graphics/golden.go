@@ -0,0 +1,115 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Golden image testing, the framebuffer is compared against a stored png to catch visual regressions.
+Like PixelAt this reads the buffer currently being drawn to so should be called from a render pass or post render hook.
+*/
+
+// CompareToGolden ... compare the framebuffer against the png at path, pixels match if every channel differs by at most
+// tolerance (0 to 1). Returns whether every pixel matched and the percentage of mismatched pixels. On a mismatch a diff
+// image highlighting mismatched pixels in red is written next to the golden image with a _diff suffix.
+func CompareToGolden(path string, tolerance float32) (bool, float32, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return false, 0, fmt.Errorf("cannot open golden image %s: %v", path, err)
+	}
+
+	golden, err := png.Decode(file)
+	file.Close()
+
+	if err != nil {
+		return false, 0, fmt.Errorf("cannot decode golden image %s: %v", path, err)
+	}
+
+	frame := readFramebuffer()
+
+	if golden.Bounds().Size() != frame.Bounds().Size() {
+		return false, 100, fmt.Errorf("golden image %s is %v, framebuffer is %v", path, golden.Bounds().Size(), frame.Bounds().Size())
+	}
+
+	bounds := frame.Bounds()
+	diff := image.NewRGBA(bounds)
+	limit := uint32(tolerance * 0xFFFF)
+	mismatched := 0
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, a1 := frame.At(x, y).RGBA()
+			r2, g2, b2, a2 := golden.At(x+golden.Bounds().Min.X, y+golden.Bounds().Min.Y).RGBA()
+
+			if channelDiff(r1, r2) > limit || channelDiff(g1, g2) > limit || channelDiff(b1, b2) > limit || channelDiff(a1, a2) > limit {
+				mismatched++
+				diff.Set(x, y, color.RGBA{255, 0, 0, 255})
+
+				continue
+			}
+
+			// Dim matching pixels so mismatches stand out
+			diff.Set(x, y, color.RGBA{uint8(r1 >> 10), uint8(g1 >> 10), uint8(b1 >> 10), 255})
+		}
+	}
+
+	percentage := 100 * float32(mismatched) / float32(bounds.Dx()*bounds.Dy())
+
+	if mismatched == 0 {
+		return true, 0, nil
+	}
+
+	diffPath := strings.TrimSuffix(path, filepath.Ext(path)) + "_diff.png"
+	diffFile, err := os.Create(diffPath)
+
+	if err != nil {
+		return false, percentage, fmt.Errorf("cannot create diff image %s: %v", diffPath, err)
+	}
+
+	defer diffFile.Close()
+
+	if err := png.Encode(diffFile, diff); err != nil {
+		return false, percentage, fmt.Errorf("cannot write diff image %s: %v", diffPath, err)
+	}
+
+	return false, percentage, nil
+}
+
+// readFramebuffer ... read the whole framebuffer being drawn to, top row first
+func readFramebuffer() *image.RGBA {
+	viewport := make([]int32, 4)
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	width, height := int(viewport[2]), int(viewport[3])
+	pixels := make([]uint8, width*height*4)
+
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(viewport[0], viewport[1], viewport[2], viewport[3], gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	stride := width * 4
+
+	// Opengl rows start at the bottom
+	for y := 0; y < height; y++ {
+		copy(img.Pix[y*stride:(y+1)*stride], pixels[(height-1-y)*stride:(height-y)*stride])
+	}
+
+	return img
+}
+
+func channelDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
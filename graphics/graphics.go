@@ -1,10 +1,14 @@
 package graphics
 
 import (
+	"fmt"
 	"gopengl/graphics/opengl"
+	"math"
+	"sort"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
 )
 
 /*
@@ -27,12 +31,49 @@ func Init() {
 	}
 }
 
+/*
+Capability queries, these require a current opengl context so are only valid after Init and window creation
+*/
+
+var (
+	glMajor, glMinor int32
+	glExtensions     map[string]bool
+)
+
+// SupportedGLVersion ... the opengl version of the current context
+func SupportedGLVersion() (major, minor int) {
+	if glMajor == 0 {
+		gl.GetIntegerv(gl.MAJOR_VERSION, &glMajor)
+		gl.GetIntegerv(gl.MINOR_VERSION, &glMinor)
+	}
+
+	return int(glMajor), int(glMinor)
+}
+
+// HasExtension ... check if the current context supports the named extension, eg "GL_ARB_texture_filter_anisotropic"
+func HasExtension(name string) bool {
+	if glExtensions == nil {
+		var num int32
+		gl.GetIntegerv(gl.NUM_EXTENSIONS, &num)
+
+		glExtensions = make(map[string]bool, num)
+
+		for i := int32(0); i < num; i++ {
+			glExtensions[gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i)))] = true
+		}
+	}
+
+	return glExtensions[name]
+}
+
 var windowWidth float32 = 800
 var windowHeight float32 = 600
 
 func SetWindowSize(width, height float32) {
 	windowWidth = width
 	windowHeight = height
+
+	updateProjection()
 }
 
 /*
@@ -50,6 +91,18 @@ type RenderObject struct {
 	freeVert int
 	maxVert  int
 	ptrVars  []*float32
+	anchors  map[int]Anchor
+	live     map[int]int // Start vertex to vertex count of every live primitive
+	freeList []int       // Start vertices of removed squares available for reuse
+	region   Rect        // Region of the texture texture coordinates are relative to
+	mode     uint32      // Primitive type vertices are drawn as
+	blend    BlendMode
+	staging  *vertexStaging
+	screen   bool // Internal objects drawn in top left pixel coordinates regardless of the coordinate origin
+	depth    float32
+	alpha    float32 // Alpha of the colour mod
+	blended  bool    // Marked as transparent by SetTransparent
+	capWarn  *capacityWarning
 }
 
 var renderObjects = make([]*RenderObject, 0)
@@ -59,6 +112,11 @@ var window *glfw.Window
 
 func SetWindow(newWindow *glfw.Window) {
 	window = newWindow
+
+	if newWindow != nil {
+		framebufferEmpty = false
+		newWindow.SetFramebufferSizeCallback(onFramebufferResize)
+	}
 }
 
 func (ro *RenderObject) Vao() *opengl.VAO {
@@ -66,24 +124,304 @@ func (ro *RenderObject) Vao() *opengl.VAO {
 
 }
 
-func CreateRenderObject(obj *RenderObject, size int, texture string, defaultShader bool) {
+// SetShader ... draw the render object with program, eg from opengl.ShaderWithFragment, keeping its transformations
+// and colour settings. Must be called on the main thread.
+func (ro *RenderObject) SetShader(program *opengl.Program) {
+	ro.vao.SetShader(program)
+}
+
+// CreateRenderObject ... returns an error if the maximum number of render objects already exist
+func CreateRenderObject(obj *RenderObject, size int, texture string, defaultShader bool) error {
+	if maxRenderObjects > 0 && len(renderObjects) >= maxRenderObjects {
+		return fmt.Errorf("cannot create render object, limit of %d render objects reached", maxRenderObjects)
+	}
+
+	createRenderObject(obj, size, texture, defaultShader)
+
+	renderObjects = append(renderObjects, obj)
+
+	return nil
+}
+
+var maxRenderObjects = 0
+
+// SetMaxRenderObjects ... limit the number of live render objects to catch objects that are never deleted, 0 for no limit
+func SetMaxRenderObjects(n int) {
+	maxRenderObjects = n
+}
+
+// createRenderObject ... create a render object that is not rendered or cleaned up automatically
+func createRenderObject(obj *RenderObject, size int, texture string, defaultShader bool) {
+	size = allocSize(size)
+
 	vao := opengl.CreateVAO(uint32(size), texture, defaultShader, windowWidth, windowHeight)
 	vao.CreateBuffers()
 
 	obj.vao = vao
 	obj.texture = vao.Texture
+	obj.region = Rect{0, 0, float32(vao.Texture.Width()), float32(vao.Texture.Height())}
 	obj.freeVert = 0
 	obj.maxVert = size
+	obj.mode = gl.TRIANGLES
+	obj.alpha = 1
+	obj.anchors = make(map[int]Anchor)
+	obj.live = make(map[int]int)
+	obj.freeList = make([]int, 0)
+	obj.staging = createVertexStaging(obj)
 
 	// Init pointer vars
 	obj.InitPointers()
+}
 
-	renderObjects = append(renderObjects, obj)
+/*
+Allocation strategies, determines how many vertices are actually allocated for a requested render object size
+*/
+
+type AllocStrategy int
+
+const (
+	AllocExact      AllocStrategy = iota // Allocate exactly the requested number of vertices
+	AllocPowerOfTwo AllocStrategy = iota // Round the requested size up to the next power of two
+)
+
+var allocStrategy = AllocExact
+
+// SetAllocationStrategy ... set the strategy used for all render objects created after this call.
+func SetAllocationStrategy(strategy AllocStrategy) {
+	allocStrategy = strategy
+}
+
+func allocSize(size int) int {
+	switch allocStrategy {
+	case AllocPowerOfTwo:
+		capacity := 1
+
+		for capacity < size {
+			capacity <<= 1
+		}
+
+		return capacity
+	default:
+		return size
+	}
+}
+
+// Usage ... returns the number of used vertices and the allocated vertex capacity of the render object
+func (obj *RenderObject) Usage() (used, capacity int) {
+	return obj.freeVert, obj.maxVert
+}
+
+type capacityWarning struct {
+	threshold float32
+	callback  func(used, max int)
+	warned    bool // Whether usage is above the threshold, the callback only fires when crossing it
+}
+
+// SetCapacityWarning ... call cb when the fraction of vertices used rises to threshold or above, eg 0.9, so the
+// render object can be replaced with a larger one before it overflows. cb is called once per crossing, after usage
+// has dropped back below the threshold it is called again on the next crossing. Pass a nil cb to remove the warning.
+// Panics if threshold is not above 0 and at most 1 when cb is given.
+func (obj *RenderObject) SetCapacityWarning(threshold float32, cb func(used, max int)) {
+	if cb == nil {
+		obj.capWarn = nil
+
+		return
+	}
+
+	if threshold <= 0 || threshold > 1 {
+		panic(fmt.Errorf("capacity warning threshold must be in (0, 1], got %v", threshold))
+	}
+
+	obj.capWarn = &capacityWarning{threshold, cb, false}
+	obj.checkCapacity()
+}
+
+func (obj *RenderObject) checkCapacity() {
+	if obj.capWarn == nil {
+		return
+	}
+
+	warn := obj.capWarn
+	above := float32(obj.freeVert) >= warn.threshold*float32(obj.maxVert)
+	crossed := above && !warn.warned
+
+	// Recorded before the callback so adding vertices from inside it does not fire it again
+	warn.warned = above
+
+	if crossed {
+		warn.callback(obj.freeVert, obj.maxVert)
+	}
+}
+
+// RenderObjects ... a snapshot of all live render objects in draw order
+func RenderObjects() []*RenderObject {
+	objs := make([]*RenderObject, len(renderObjects))
+	copy(objs, renderObjects)
+
+	return objs
+}
+
+// ForEachRenderObject ... call fn for every live render object, objects can safely be created or deleted by fn
+func ForEachRenderObject(fn func(*RenderObject)) {
+	for _, obj := range RenderObjects() {
+		fn(obj)
+	}
 }
 
 func DeleteRenderObjects() {
 	for _, obj := range renderObjects {
-		obj.Delete()
+		obj.vao.Delete()
+	}
+
+	renderObjects = renderObjects[:0]
+}
+
+// BakeRenderObjects ... merge the geometry of multiple render objects into a single new render object so they can
+// be drawn in one call. Each object's translation, rotation and scale are baked into its vertices so the result renders
+// identically. All objects must share a texture, shader, camera, zoom, primitive mode, blend mode, depth, texture region,
+// palette, colour mod, alpha threshold, fill and samplers, which are copied to the result, otherwise an error is
+// returned. Anchors are kept. The original render objects are left untouched.
+func BakeRenderObjects(objs []*RenderObject) (*RenderObject, error) {
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("no render objects given to bake")
+	}
+
+	first := objs[0]
+	texture := first.texture
+	size := 0
+
+	for _, obj := range objs {
+		if obj.texture != texture {
+			return nil, fmt.Errorf("cannot bake render objects with different textures: %s and %s", texture.File(), obj.texture.File())
+		}
+
+		// The camera and zoom are not applied to vertices so cannot differ
+		for _, ptr := range []int{camXPtr, camYPtr, zoomPtr} {
+			if *obj.ptrVars[ptr] != *first.ptrVars[ptr] {
+				return nil, fmt.Errorf("cannot bake render objects with different cameras or zooms")
+			}
+		}
+
+		if state := bakedStateMismatch(first, obj); state != "" {
+			return nil, fmt.Errorf("cannot bake render objects with different %s", state)
+		}
+
+		size += obj.freeVert
+	}
+
+	baked := &RenderObject{}
+	err := CreateRenderObject(baked, size, texture.File(), first.vao.UsesDefaultShader())
+
+	if err != nil {
+		return nil, err
+	}
+
+	copyBakedState(baked, first)
+
+	offset := 0
+	verts := baked.vao.Verts()
+	texs := baked.vao.Texs()
+	colours := baked.vao.Colours()
+
+	for _, obj := range objs {
+		for start, count := range obj.live {
+			baked.live[offset+start] = count
+		}
+
+		for _, start := range obj.freeList {
+			baked.freeList = append(baked.freeList, offset+start)
+		}
+
+		for start, anchor := range obj.anchors {
+			baked.anchors[offset+start] = anchor
+		}
+
+		// Rotations and scales are baked, the baked object's grouped rotations are left as identities
+		transformed := obj.vao.TransformedVerts(obj.freeVert, *obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr])
+
+		copy(verts[offset*opengl.DEFAULT_VECTOR_SIZE:], transformed)
+		copy(texs[offset*opengl.DEFAULT_TEXS_SIZE:], obj.vao.Texs()[:obj.freeVert*opengl.DEFAULT_TEXS_SIZE])
+		copy(colours[offset*opengl.DEFAULT_COLOUR_SIZE:], obj.vao.Colours()[:obj.freeVert*opengl.DEFAULT_COLOUR_SIZE])
+
+		offset += obj.freeVert
+	}
+
+	baked.SetCamera(first.ptrVars[camXPtr], first.ptrVars[camYPtr])
+	baked.SetZoom(first.ptrVars[zoomPtr])
+
+	baked.vao.UpdateBuffers()
+	baked.freeVert = size
+
+	return baked, nil
+}
+
+// Shader uniforms set by render object methods which are not baked into vertices
+var bakedUniforms = []string{"colourmul", "colouradd", "alphathreshold", "fill", "filled"}
+
+// bakedStateMismatch ... the render state obj does not share with first, or an empty string if it all matches
+func bakedStateMismatch(first, obj *RenderObject) string {
+	switch {
+	case first.vao.UsesDefaultShader() != obj.vao.UsesDefaultShader() ||
+		!first.vao.UsesDefaultShader() && first.vao.Shader() != obj.vao.Shader():
+		return "shaders"
+	case first.mode != obj.mode:
+		return "primitive modes"
+	case first.blend != obj.blend:
+		return "blend modes"
+	case first.screen != obj.screen || first.blended != obj.blended:
+		return "render passes"
+	case first.depth != obj.depth:
+		return "depths"
+	case first.region != obj.region:
+		return "texture regions"
+	case first.vao.Palette() != obj.vao.Palette():
+		return "palettes"
+	}
+
+	for _, name := range bakedUniforms {
+		if first.vao.Uniform(name) != obj.vao.Uniform(name) {
+			return "colour mods, alpha thresholds or fills"
+		}
+	}
+
+	firstSamplers, samplers := first.vao.Samplers(), obj.vao.Samplers()
+
+	if len(firstSamplers) != len(samplers) {
+		return "samplers"
+	}
+
+	for unit, s := range firstSamplers {
+		if samplers[unit] != s {
+			return "samplers"
+		}
+	}
+
+	return ""
+}
+
+// copyBakedState ... give baked the render state shared by the baked objects, matching bakedStateMismatch
+func copyBakedState(baked, first *RenderObject) {
+	if !first.vao.UsesDefaultShader() {
+		baked.SetShader(first.vao.Shader())
+	}
+
+	baked.mode = first.mode
+	baked.blend = first.blend
+	baked.screen = first.screen
+	baked.blended = first.blended
+	baked.region = first.region
+	baked.alpha = first.alpha
+	baked.SetDepth(first.depth)
+	baked.SetPalette(first.vao.Palette())
+
+	for _, name := range bakedUniforms {
+		if value := first.vao.Uniform(name); value != nil {
+			baked.vao.SetUniform(name, value)
+		}
+	}
+
+	for unit, s := range first.vao.Samplers() {
+		baked.SetSampler(unit, s)
 	}
 }
 
@@ -92,19 +430,117 @@ Render Object methods
 */
 
 func Render() {
-	gl.ClearColor(0.0, 0.0, 0.0, 1.0)
-	gl.Clear(gl.COLOR_BUFFER_BIT)
-	for _, obj := range renderObjects {
-		obj.Render()
+	updateDeltaTime()
+
+	// Minimized windows can have no framebuffer to draw into, keep polling until they are restored
+	if framebufferEmpty {
+		Poll(window)
+
+		return
+	}
+
+	if renderScale != 1 {
+		renderSupersampled()
+	} else {
+		renderScene()
 	}
 
+	endFrameStats()
+	evictTextures()
+
 	Poll(window)
 }
 
+// RenderToFBO ... render the scene into an existing framebuffer of w by h pixels, eg one owned by a host application.
+// The previously bound draw and read framebuffers and viewport are restored afterwards, buffers are not swapped.
+func RenderToFBO(fbo uint32, w, h int) {
+	draw, read := framebufferBindings()
+	viewport := make([]int32, 4)
+
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, fbo)
+	gl.Viewport(0, 0, int32(w), int32(h))
+
+	renderScene()
+
+	restoreFramebuffers(draw, read)
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+}
+
+// framebufferBindings ... the currently bound draw and read framebuffers, which can differ in a host application
+func framebufferBindings() (draw, read uint32) {
+	var drawFBO, readFBO int32
+
+	gl.GetIntegerv(gl.DRAW_FRAMEBUFFER_BINDING, &drawFBO)
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &readFBO)
+
+	return uint32(drawFBO), uint32(readFBO)
+}
+
+func restoreFramebuffers(draw, read uint32) {
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, draw)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, read)
+}
+
+func renderScene() {
+	gl.ClearColor(0.0, 0.0, 0.0, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.STENCIL_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	renderBackground()
+	renderPassList()
+	renderDebug()
+	renderOverlays()
+}
+
 func (obj *RenderObject) Render() {
+	if obj.blend != BlendInherit {
+		applyBlendMode(obj.blend)
+	}
+
 	vertNum := obj.PrepRender()
-	gl.DrawArrays(gl.TRIANGLES, 0, vertNum)
+	gl.DrawArrays(obj.mode, 0, vertNum)
+	countDraw(vertNum)
 	obj.FinishRender()
+
+	if obj.blend != BlendInherit {
+		applyBlendMode(globalBlendMode)
+	}
+}
+
+// SetPrimitive ... set the primitive type vertices are drawn as, eg gl.POINTS or gl.LINES. Defaults to gl.TRIANGLES,
+// the square and rectangle methods always add triangles.
+func (obj *RenderObject) SetPrimitive(mode uint32) {
+	obj.mode = mode
+}
+
+// SetPointSize ... set the size in pixels that gl.POINTS primitives are drawn with. The maximum size varies by
+// driver, larger sizes are clamped to it. Panics if px is not positive. The default shader does not write gl_PointSize
+// so program point sizes stay disabled, see SetProgramPointSize for custom shaders that do.
+func SetPointSize(px float32) {
+	if px <= 0 {
+		panic(fmt.Errorf("point size must be positive, got %v", px))
+	}
+
+	pointRange := make([]float32, 2)
+	gl.GetFloatv(gl.POINT_SIZE_RANGE, &pointRange[0])
+
+	if px > pointRange[1] {
+		px = pointRange[1]
+	}
+
+	gl.PointSize(px)
+}
+
+// SetProgramPointSize ... use the gl_PointSize written by the vertex shader instead of SetPointSize, for custom vertex
+// shaders sizing each point. Disabled by default, shaders which do not write gl_PointSize draw undefined sizes when enabled.
+func SetProgramPointSize(enabled bool) {
+	if enabled {
+		gl.Enable(gl.PROGRAM_POINT_SIZE)
+
+		return
+	}
+
+	gl.Disable(gl.PROGRAM_POINT_SIZE)
 }
 
 func (obj *RenderObject) PrepRender() int32 {
@@ -116,8 +552,17 @@ func (obj *RenderObject) FinishRender() {
 	obj.vao.FinishRender()
 }
 
+// Delete ... delete the render object's VAO and stop rendering it
 func (obj *RenderObject) Delete() {
 	obj.vao.Delete()
+
+	for i, existing := range renderObjects {
+		if existing == obj {
+			renderObjects = append(renderObjects[:i], renderObjects[i+1:]...)
+
+			break
+		}
+	}
 }
 
 // AddSquare ... add a square to the render object, position is from the top left in pixels
@@ -135,35 +580,81 @@ func (obj *RenderObject) AddSquare(x, y, xTex, yTex, width, widthTex float32) in
 		x, y + width,
 	}
 
+	heightTex := widthTex
+	yTex, heightTex = obj.flipTexY(yTex, heightTex)
+
 	texs := []float32{
 		// Upper right triangle
 		xTex, yTex,
 		xTex + widthTex, yTex,
-		xTex + widthTex, yTex + widthTex,
+		xTex + widthTex, yTex + heightTex,
 
 		// Lower left triangle
 		xTex, yTex,
-		xTex + widthTex, yTex + widthTex,
-		xTex, yTex + widthTex,
+		xTex + widthTex, yTex + heightTex,
+		xTex, yTex + heightTex,
 	}
 
 	// Removed as vertex scaling performed in shader
 	// verts = PixToScreen(verts)
-	texs = obj.texture.PixToTex(texs)
+	texs = obj.pixToTex(texs)
 
-	if obj.freeVert+6 > obj.maxVert {
-		panic("Render Object Buffer overflow")
-	}
-
-	obj.vao.UpdateBufferIndex(obj.freeVert, verts, texs)
-	obj.freeVert += 6
+	index := obj.allocate(6)
+	obj.vao.UpdateBufferIndex(index, verts, texs)
 
-	return obj.freeVert - 6
+	return index
 }
 
 // 	return obj.AddRect(x, y, xTex, yTex, width, width, widthTex, widthTex)
 // }
 
+/*
+Anchors, the point of a square positioned at x, y and pivoted about by RotateSquare
+*/
+
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// offset ... fraction of the width from the top left to the anchor point
+func (a Anchor) offset() (x, y float32) {
+	return float32(a%3) / 2, float32(a/3) / 2
+}
+
+// AddSquareAnchored ... add a square with its anchor point at x, y
+func (obj *RenderObject) AddSquareAnchored(x, y, width float32, anchor Anchor, xTex, yTex, widthTex float32) int {
+	oX, oY := anchor.offset()
+	index := obj.AddSquare(x-oX*width, y-oY*width, xTex, yTex, width, widthTex)
+
+	obj.anchors[index] = anchor
+
+	return index
+}
+
+// RotateSquare ... rotate a square by rad radians about its anchor, squares not added with an anchor pivot about their top left
+func (obj *RenderObject) RotateSquare(index int, rad float32) {
+	verts := obj.vao.Verts()
+	vIndex := index * opengl.DEFAULT_VECTOR_SIZE
+
+	// First vertex is the top left, second the top right
+	x, y := verts[vIndex], verts[vIndex+1]
+	width := verts[vIndex+2] - x
+
+	oX, oY := obj.anchors[index].offset()
+	obj.vao.SetGroupedRotation(x+oX*width, y+oY*width, rad, index, index+6)
+	obj.vao.UpdateBuffers()
+}
+
 func (obj *RenderObject) AddRect(x, y, xTex, yTex, width, height, widthTex, heightTex float32) int {
 	verts := []float32{
 		// Upper right triangle
@@ -177,6 +668,8 @@ func (obj *RenderObject) AddRect(x, y, xTex, yTex, width, height, widthTex, heig
 		x, y + height,
 	}
 
+	yTex, heightTex = obj.flipTexY(yTex, heightTex)
+
 	texs := []float32{
 		// Upper right triangle
 		xTex, yTex,
@@ -191,16 +684,12 @@ func (obj *RenderObject) AddRect(x, y, xTex, yTex, width, height, widthTex, heig
 
 	// Removed as vertex scaling performed in shader now.
 	// verts = PixToScreen(verts)
-	texs = obj.texture.PixToTex(texs)
+	texs = obj.pixToTex(texs)
 
-	if obj.freeVert+6 > obj.maxVert {
-		panic("Render Object Buffer overflow")
-	}
-
-	obj.vao.UpdateBufferIndex(obj.freeVert, verts, texs)
-	obj.freeVert += 6
+	index := obj.allocate(6)
+	obj.vao.UpdateBufferIndex(index, verts, texs)
 
-	return obj.freeVert - 6
+	return index
 }
 
 func (obj *RenderObject) ModifyVertSquare(index int, x, y, width float32) {
@@ -217,7 +706,7 @@ func (obj *RenderObject) ModifyVertRect(index int, x, y, width, height float32)
 		// Lower left triangle
 		x, y,
 		x + width, y + height,
-		x, y - height,
+		x, y + height,
 	}
 
 	// verts = PixToScreen(verts)
@@ -234,6 +723,48 @@ func (obj *RenderObject) ModifyTexSquare(index int, xTex, yTex, widthTex float32
 }
 
 func (obj *RenderObject) ModifyTexRect(index int, xTex, yTex, widthTex, heightTex float32) {
+	obj.vao.UpdateTexBufferIndex(index, obj.rectTexs(xTex, yTex, widthTex, heightTex))
+}
+
+// SetFrames ... set the texture coordinates of the square at each index to the matching frame in pixels, uploading
+// every change at once. Much faster than ModifyTexRect for animating many squares each frame.
+func (obj *RenderObject) SetFrames(indices []int, frames []Rect) {
+	if len(indices) != len(frames) {
+		panic(fmt.Errorf("%d indices given for %d frames", len(indices), len(frames)))
+	}
+
+	if len(indices) == 0 {
+		return
+	}
+
+	first, last := indices[0], indices[0]
+
+	for _, index := range indices {
+		if index < first {
+			first = index
+		}
+
+		if index > last {
+			last = index
+		}
+	}
+
+	// Squares between the changed ones are uploaded unchanged
+	texs := make([]float32, (last+6-first)*opengl.DEFAULT_TEXS_SIZE)
+	copy(texs, obj.vao.Texs()[first*opengl.DEFAULT_TEXS_SIZE:])
+
+	for i, index := range indices {
+		frame := frames[i]
+		copy(texs[(index-first)*opengl.DEFAULT_TEXS_SIZE:], obj.rectTexs(frame.X, frame.Y, frame.Width, frame.Height))
+	}
+
+	obj.vao.UpdateTexBufferRange(first, texs)
+}
+
+// rectTexs ... normalized texture coordinates of a rect's vertices from pixels
+func (obj *RenderObject) rectTexs(xTex, yTex, widthTex, heightTex float32) []float32 {
+	yTex, heightTex = obj.flipTexY(yTex, heightTex)
+
 	texs := []float32{
 		// Upper right triangle
 		xTex, yTex,
@@ -246,9 +777,7 @@ func (obj *RenderObject) ModifyTexRect(index int, xTex, yTex, widthTex, heightTe
 		xTex, yTex + heightTex,
 	}
 
-	texs = obj.texture.PixToTex(texs)
-
-	obj.vao.UpdateTexBufferIndex(index, texs)
+	return obj.pixToTex(texs)
 }
 
 func (obj *RenderObject) ModifySquare(index int, x, y, xTex, yTex, width, widthTex float32) {
@@ -261,6 +790,145 @@ func (obj *RenderObject) ModifyRect(index int, x, y, xTex, yTex, width, height,
 	obj.ModifyTexRect(index, x, y, widthTex, heightTex)
 }
 
+// SetColor ... set the colour the texture is multiplied by for count vertices from index
+func (obj *RenderObject) SetColor(index, count int, c Color) {
+	colours := make([]float32, count*opengl.DEFAULT_COLOUR_SIZE)
+
+	for i := 0; i < len(colours); i += opengl.DEFAULT_COLOUR_SIZE {
+		colours[i] = c.R
+		colours[i+1] = c.G
+		colours[i+2] = c.B
+		colours[i+3] = c.A
+	}
+
+	obj.vao.UpdateColourBufferIndex(index, colours)
+}
+
+// RemoveSquare ... clear a square and free its vertices to be reused by the next added square. Panics if index is
+// not the start of a live square, other primitives cannot be removed as freed vertices are only reused by squares.
+func (obj *RenderObject) RemoveSquare(index int) {
+	count, exists := obj.live[index]
+
+	if !exists {
+		panic(fmt.Errorf("no square at index %d to remove", index))
+	}
+
+	if count != 6 {
+		panic(fmt.Errorf("cannot remove the %d vertex primitive at index %d, only squares can be removed", count, index))
+	}
+
+	obj.vao.ClearVertices(index, 6)
+	obj.vao.UpdateBuffers()
+
+	delete(obj.live, index)
+	delete(obj.anchors, index)
+	obj.freeList = append(obj.freeList, index)
+}
+
+// Defragment ... move all live squares to the front of the buffer removing any gaps left by removed squares.
+// Returns a map of old to new indices, any stored indices of squares are invalidated and must be updated from it.
+func (obj *RenderObject) Defragment() map[int]int {
+	starts := make([]int, 0, len(obj.live))
+
+	for start := range obj.live {
+		starts = append(starts, start)
+	}
+
+	sort.Ints(starts)
+
+	moved := make(map[int]int, len(starts))
+	live := make(map[int]int, len(starts))
+	anchors := make(map[int]Anchor, len(obj.anchors))
+	freeVert := 0
+
+	for _, start := range starts {
+		count := obj.live[start]
+
+		if start != freeVert {
+			obj.vao.CopyVertices(freeVert, start, count)
+		}
+
+		moved[start] = freeVert
+		live[freeVert] = count
+
+		if anchor, exists := obj.anchors[start]; exists {
+			anchors[freeVert] = anchor
+		}
+
+		freeVert += count
+	}
+
+	obj.vao.ClearVertices(freeVert, obj.freeVert-freeVert)
+	obj.vao.UpdateBuffers()
+
+	obj.live = live
+	obj.anchors = anchors
+	obj.freeList = obj.freeList[:0]
+	obj.freeVert = freeVert
+	obj.checkCapacity()
+
+	return moved
+}
+
+// reset ... remove every square at once
+func (obj *RenderObject) reset() {
+	obj.vao.ClearVertices(0, obj.freeVert)
+	obj.vao.UpdateBuffers()
+	obj.replaceVertices(0, 0)
+}
+
+// replaceVertices ... forget every square, the first count vertices become live primitives of size vertices each
+func (obj *RenderObject) replaceVertices(count, size int) {
+	obj.freeVert = count
+	obj.live = make(map[int]int)
+	obj.anchors = make(map[int]Anchor)
+	obj.freeList = obj.freeList[:0]
+
+	for i := 0; i < count; i += size {
+		obj.live[i] = size
+	}
+
+	obj.checkCapacity()
+}
+
+// Fill ... generate the render object's geometry directly for meshes that do not fit the square helpers. fn is given
+// the vertex buffer, in pixels, and texture coordinate buffer, normalized over the whole texture, for the render
+// object's whole capacity and returns the number of vertices it wrote. All existing geometry is replaced.
+func (obj *RenderObject) Fill(fn func(verts, texs []float32) int) {
+	obj.vao.ClearVertices(0, obj.freeVert)
+
+	count := fn(obj.vao.Verts(), obj.vao.Texs())
+
+	if count < 0 || count > obj.maxVert {
+		panic(fmt.Errorf("fill wrote %d vertices, render object has a capacity of %d", count, obj.maxVert))
+	}
+
+	obj.vao.UpdateBuffers()
+	obj.replaceVertices(count, count)
+}
+
+// allocate ... find space for count vertices, reusing removed squares where possible
+func (obj *RenderObject) allocate(count int) int {
+	if count == 6 && len(obj.freeList) > 0 {
+		index := obj.freeList[len(obj.freeList)-1]
+		obj.freeList = obj.freeList[:len(obj.freeList)-1]
+		obj.live[index] = count
+
+		return index
+	}
+
+	if obj.freeVert+count > obj.maxVert {
+		panic("Render Object Buffer overflow")
+	}
+
+	index := obj.freeVert
+	obj.freeVert += count
+	obj.live[index] = count
+	obj.checkCapacity()
+
+	return index
+}
+
 // Clear a square, does not delete the object.
 func (obj *RenderObject) ClearSquare(index int) {
 	obj.ModifyVertSquare(index, 0, 0, 0)
@@ -272,6 +940,111 @@ func (obj *RenderObject) Rotate(x, y, rad float32) {
 	obj.vao.SetRotation(nX, nY, rad)
 }
 
+// BakeTransform ... apply the current translation, rotation and scale directly to the vertices and reset them,
+// avoiding per frame transformation of static objects. This cannot be reversed, translation pointers are replaced.
+func (obj *RenderObject) BakeTransform() {
+	var (
+		x float32 = 0
+		y float32 = 0
+	)
+
+	// Translations are in the same coordinates as vertices
+	obj.vao.BakeTransform(*obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr])
+	obj.SetTranslate(&x, &y)
+}
+
+// Bounds ... the bounding rectangle of all used vertices in pixels, before any transformations
+func (obj *RenderObject) Bounds() (x, y, width, height float32) {
+	verts := obj.vao.Verts()[:obj.freeVert*opengl.DEFAULT_VECTOR_SIZE]
+
+	if len(verts) == 0 {
+		return 0, 0, 0, 0
+	}
+
+	minX, minY := verts[0], verts[1]
+	maxX, maxY := minX, minY
+
+	for i := 0; i < len(verts); i += 2 {
+		minX = float32(math.Min(float64(minX), float64(verts[i])))
+		maxX = float32(math.Max(float64(maxX), float64(verts[i])))
+		minY = float32(math.Min(float64(minY), float64(verts[i+1])))
+		maxY = float32(math.Max(float64(maxY), float64(verts[i+1])))
+	}
+
+	return minX, minY, maxX - minX, maxY - minY
+}
+
+// SetPalette ... recolour the render object using palette, the texture's red channel is used as an index into the
+// first row of the palette. Pass nil to use the texture's colours directly.
+func (obj *RenderObject) SetPalette(p *opengl.Texture) {
+	obj.vao.SetPalette(p)
+}
+
+// SetTexture ... swap the render object's texture, existing texture coordinates keep their pixel positions in the new texture
+func (obj *RenderObject) SetTexture(t *opengl.Texture) {
+	obj.SetTextureRegion(t, Rect{0, 0, float32(t.Width()), float32(t.Height())})
+}
+
+// SetTextureRegion ... use a region of t, such as an atlas page, all texture coordinates become relative to the region.
+// Existing texture coordinates are remapped into the new region.
+func (obj *RenderObject) SetTextureRegion(t *opengl.Texture, region Rect) {
+	original := obj.vao.Texs()[:obj.freeVert*opengl.DEFAULT_TEXS_SIZE]
+	texs := obj.texToPix(original)
+
+	obj.texture = t
+	obj.vao.Texture = t
+	obj.region = region
+
+	texs = obj.pixToTex(texs)
+	keepCircleTexs(texs, original)
+
+	obj.vao.UpdateTexBufferIndex(0, texs)
+}
+
+// pixToTex ... normalize pixel texture coordinates relative to the texture region
+func (obj *RenderObject) pixToTex(texs []float32) []float32 {
+	offset := make([]float32, len(texs))
+
+	for i := 0; i < len(texs); i += 2 {
+		offset[i] = texs[i] + obj.region.X
+		offset[i+1] = texs[i+1] + obj.region.Y
+	}
+
+	return obj.texture.PixToTex(offset)
+}
+
+// texToPix ... inverse of pixToTex
+func (obj *RenderObject) texToPix(texs []float32) []float32 {
+	pixels := make([]float32, len(texs))
+	width := float32(obj.texture.Width())
+	height := float32(obj.texture.Height())
+
+	for i := 0; i < len(texs); i += 2 {
+		pixels[i] = texs[i]*width - obj.region.X
+		pixels[i+1] = texs[i+1]*height - obj.region.Y
+	}
+
+	return pixels
+}
+
+// SetColorMod ... every fragment is drawn as texel*mul + add, add is useful for flashing an object white on hit.
+// Defaults to a white mul and transparent add, leaving the texture unchanged.
+func (obj *RenderObject) SetColorMod(mul, add Color) {
+	obj.alpha = mul.A
+	obj.vao.SetColourMod(mul.vec4(), add.vec4())
+}
+
+// SetAlphaThreshold ... discard fragments with an alpha below t, for cutout sprites with hard edges. Defaults to 0, nothing is discarded.
+func (obj *RenderObject) SetAlphaThreshold(t float32) {
+	obj.vao.SetAlphaThreshold(t)
+}
+
+// SetDepth ... set the depth of the whole render object, must be within the depth range to be visible
+func (obj *RenderObject) SetDepth(depth float32) {
+	obj.depth = depth
+	obj.vao.SetDepth(depth)
+}
+
 /*
 Rotation group methods
 */
@@ -335,8 +1108,29 @@ func (obj *RenderObject) PrepPointers() {
 	obj.vao.SetCamera(nX, nY)
 
 	// Set Translation
-	nX, nY = NormVert(*obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr])
-	obj.vao.SetTranslation(nX, nY-2)
+	switch {
+	case obj.screen:
+		obj.vao.SetProjection(screenProjection)
+
+		nX, nY = NormVert(*obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr])
+		obj.vao.SetTranslation(nX*flipX, -nY*flipY)
+	case customProjection:
+		// Translate before projecting so translations are in vertex coordinates
+		obj.vao.SetProjection(projection.Mul4(mgl32.Translate3D(*obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr], 0)))
+		obj.vao.SetTranslation(0, 0)
+	case coordinateOrigin == OriginTopLeft:
+		obj.vao.SetProjection(projection)
+
+		nX, nY = NormVert(*obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr])
+		// Translation is applied after projection where y is up, so is inverted to move down the window
+		obj.vao.SetTranslation(nX*flipX, -nY*flipY)
+	default:
+		obj.vao.SetProjection(projection)
+
+		// y is already up in the origin and after projection
+		nX, nY = NormVert(*obj.ptrVars[transXPtr], *obj.ptrVars[transYPtr])
+		obj.vao.SetTranslation(nX*flipX, nY*flipY)
+	}
 
 	// Set zoom
 	obj.vao.SetZoom(*obj.ptrVars[zoomPtr])
@@ -346,15 +1140,53 @@ func (obj *RenderObject) PrepPointers() {
 Utility methods
 */
 
+// Rect ... rectangle in pixels from its top left corner
+type Rect struct {
+	X, Y, Width, Height float32
+}
+
 func NormVert(x, y float32) (nX, nY float32) {
+	if windowWidth == 0 || windowHeight == 0 {
+		return 0, 0
+	}
+
 	nX = x / (windowWidth / 2)
 	nY = y / (windowHeight / 2)
 
 	return nX, nY
 }
 
+// PixelAt ... read the colour of a single pixel of the framebuffer, px, py are in pixels from the coordinate origin.
+// Reads the buffer currently being drawn to so should be called during a render pass. Pixels are scaled to the current
+// viewport so reads from a bound framebuffer object or supersampling target are also correct.
+func PixelAt(px, py float32) (Color, error) {
+	px, py = toTopLeft(px, py)
+
+	if px < 0 || py < 0 || px >= windowWidth || py >= windowHeight {
+		return Color{}, fmt.Errorf("pixel %v, %v is outside of the window", px, py)
+	}
+
+	// The viewport covers whatever is being drawn to, which may not be the window's framebuffer
+	viewport := make([]int32, 4)
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	fX := viewport[0] + int32(px*float32(viewport[2])/windowWidth)
+	fY := viewport[1] + viewport[3] - 1 - int32(py*float32(viewport[3])/windowHeight)
+
+	pixel := make([]uint8, 4)
+	gl.ReadPixels(fX, fY, 1, 1, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixel))
+
+	return Color{
+		float32(pixel[0]) / 255,
+		float32(pixel[1]) / 255,
+		float32(pixel[2]) / 255,
+		float32(pixel[3]) / 255,
+	}, nil
+}
+
 // should not be used with default shader, scaling occurs by default.
 func PixToScreen(coords []float32) []float32 {
+	coords = pointsToTopLeft(coords)
 	normedCoords := make([]float32, len(coords))
 	even := false
 
@@ -365,6 +1197,11 @@ func PixToScreen(coords []float32) []float32 {
 	halfWidth := windowWidth / 2
 	halfHeight := windowHeight / 2
 
+	// A zero size window has no screen coordinates, avoid producing NaNs
+	if halfWidth == 0 || halfHeight == 0 {
+		return normedCoords
+	}
+
 	for i, coord := range coords {
 		even = !even
 
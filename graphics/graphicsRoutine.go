@@ -87,6 +87,44 @@ var (
 	renderSleep      time.Duration
 )
 
+/*
+Frame timing
+*/
+
+var (
+	deltaTime    float32
+	maxDeltaTime float32 = 0.1
+	lastFrame    float64
+	firstFrame   = true
+)
+
+// DeltaTime ... time in seconds between the last two rendered frames
+func DeltaTime() float32 {
+	return deltaTime
+}
+
+// SetMaxDeltaTime ... clamp DeltaTime to at most d seconds so a stalled application (debugger, dragged window)
+// does not cause a single huge step. Defaults to 0.1s, d <= 0 disables clamping. With a fixed timestep loop the
+// clamp also bounds how many steps one frame can run.
+func SetMaxDeltaTime(d float32) {
+	maxDeltaTime = d
+}
+
+func updateDeltaTime() {
+	t := Now()
+
+	if !firstFrame {
+		deltaTime = float32(t - lastFrame)
+
+		if maxDeltaTime > 0 && deltaTime > maxDeltaTime {
+			deltaTime = maxDeltaTime
+		}
+	}
+
+	lastFrame = t
+	firstFrame = false
+}
+
 func callRenderObjectJob(job RenderObjectJob) {
 	job.callable(job)
 
@@ -114,7 +152,9 @@ These are all called *Inside* the main thread which the opengl context is runnin
 */
 
 func callCreateRenderObject(job RenderObjectJob) {
-	CreateRenderObject(job.obj, job.params[0].(int), job.params[1].(string), job.params[2].(bool))
+	err := CreateRenderObject(job.obj, job.params[0].(int), job.params[1].(string), job.params[2].(bool))
+
+	*(*error)(job.retVal) = err
 }
 
 func callAddSquare(job RenderObjectJob) {
@@ -240,13 +280,19 @@ Graphics job methods, these enqueue the job to be performed, graphics.go methods
 These are all called *Outside* the main thread which the opengl context is running on.
 */
 
-func CreateRenderObjectJob(ro *RenderObject, size int, texture string, defaultShader bool) {
+// CreateRenderObjectJob ... the returned error is set once the job has run, non nil if the maximum number of render
+// objects already exist
+func CreateRenderObjectJob(ro *RenderObject, size int, texture string, defaultShader bool) *error {
+	var err error
+
 	RenderObjectQueue <- RenderObjectJob{
 		ro,
 		[]interface{}{size, texture, defaultShader},
-		nil,
+		unsafe.Pointer(&err),
 		callCreateRenderObject,
 	}
+
+	return &err
 }
 
 func (obj *RenderObject) AddSquareJob(x, y, xTex, yTex, width, widthTex float32) *int {
@@ -344,6 +390,10 @@ Cleanup
 
 func cleanUp() {
 	DeleteRenderObjects()
+	deleteDebug()
+	deleteSupersampling()
+	deleteBackground()
+	opengl.DeleteImmediate()
 	window.Destroy()
 }
 
@@ -0,0 +1,107 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"image"
+	"runtime"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// withTestContext ... run fn with a hidden window's opengl context current, skipping where no display is available.
+// Contexts are current per os thread so the calling go routine is locked to its thread.
+func withTestContext(tb testing.TB, fn func()) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := glfw.Init(); err != nil {
+		tb.Skipf("cannot initialize glfw: %v", err)
+	}
+
+	glfw.WindowHint(glfw.Visible, glfw.False)
+	testWindow, err := createWindow(Options{Width: 256, Height: 256, Title: "gopengl test"})
+
+	if err != nil {
+		tb.Skipf("cannot create window: %v", err)
+	}
+
+	defer testWindow.Destroy()
+
+	if err := gl.Init(); err != nil {
+		tb.Skipf("cannot initialize opengl: %v", err)
+	}
+
+	SetWindow(testWindow)
+	SetWindowSize(256, 256)
+
+	fn()
+
+	opengl.ResetShaders()
+}
+
+// createSpriteObject ... a render object of count 16x16 squares on a 256x256 sprite texture
+func createSpriteObject(count int) *RenderObject {
+	opengl.TextureFromImage("gopengl:test:sprites", image.NewRGBA(image.Rect(0, 0, 256, 256)))
+
+	obj := &RenderObject{}
+	createRenderObject(obj, count*6, "gopengl:test:sprites", true)
+
+	for i := 0; i < count; i++ {
+		obj.AddSquare(float32(i%16)*16, float32(i/16)*16, 0, 0, 16, 16)
+	}
+
+	return obj
+}
+
+// deleteSpriteObject ... delete obj and its texture, which would otherwise outlive the test context
+func deleteSpriteObject(obj *RenderObject) {
+	obj.Delete()
+	opengl.DeleteTexture(obj.texture)
+}
+
+const benchmarkSprites = 1024
+
+func BenchmarkSetFrames(b *testing.B) {
+	withTestContext(b, func() {
+		obj := createSpriteObject(benchmarkSprites)
+		defer deleteSpriteObject(obj)
+
+		indices := make([]int, benchmarkSprites)
+		frames := make([]Rect, benchmarkSprites)
+
+		for i := range indices {
+			indices[i] = i * 6
+		}
+
+		b.ResetTimer()
+
+		for n := 0; n < b.N; n++ {
+			for i := range frames {
+				frames[i] = Rect{float32((n+i)%16) * 16, 0, 16, 16}
+			}
+
+			obj.SetFrames(indices, frames)
+		}
+
+		gl.Finish()
+	})
+}
+
+func BenchmarkModifyTexRect(b *testing.B) {
+	withTestContext(b, func() {
+		obj := createSpriteObject(benchmarkSprites)
+		defer deleteSpriteObject(obj)
+
+		b.ResetTimer()
+
+		for n := 0; n < b.N; n++ {
+			for i := 0; i < benchmarkSprites; i++ {
+				obj.ModifyTexRect(i*6, float32((n+i)%16)*16, 0, 16, 16)
+			}
+		}
+
+		gl.Finish()
+	})
+}
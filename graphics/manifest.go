@@ -0,0 +1,56 @@
+package graphics
+
+import (
+	"Gopengl/util"
+	"encoding/json"
+	"fmt"
+	"gopengl/graphics/opengl"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+/*
+Resource manifests, a json file listing assets by name so they can be referenced by name instead of file path, eg
+{
+	"textures": {
+		"player": "sprites/player.png"
+	}
+}
+Asset paths are relative to the manifest's directory.
+*/
+
+type manifest struct {
+	Textures map[string]string `json:"textures"`
+}
+
+// LoadManifest ... load every asset listed in the manifest, textures can then be used by name in CreateRenderObject.
+// Every asset is attempted, the returned error lists all assets that could not be loaded.
+func LoadManifest(manifestPath string) error {
+	data, err := ioutil.ReadFile(util.RelativePath(manifestPath))
+
+	if err != nil {
+		return fmt.Errorf("unable to read manifest %s: %v", manifestPath, err)
+	}
+
+	var m manifest
+
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("unable to parse manifest %s: %v", manifestPath, err)
+	}
+
+	dir := path.Dir(manifestPath)
+	failed := make([]string, 0)
+
+	for name, file := range m.Textures {
+		if _, err := opengl.LoadTextureNamed(name, path.Join(dir, file)); err != nil {
+			failed = append(failed, fmt.Sprintf("texture %s: %v", name, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to load %d assets from manifest %s:\n%s", len(failed), manifestPath, strings.Join(failed, "\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package opengl
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Context recreation, everything created in one opengl context is recreated in a new one from cpu side data.
+Textures and VAOs keep their identity so existing pointers to them remain valid.
+*/
+
+// SnapshotTextures ... read every stored texture back to the cpu ready for RestoreTextures, must be called before
+// the old context is destroyed
+func SnapshotTextures() {
+	for _, tex := range storedTextures {
+		tex.snapshot = tex.Pixels()
+	}
+}
+
+// RestoreTextures ... recreate every stored texture in the current context from its snapshot. Textures are
+// restored as 8 bit rgba, higher precision formats lose their precision.
+func RestoreTextures() {
+	for _, tex := range storedTextures {
+		activeTexture(textureUnits[tex.textureUnit])
+		gl.GenTextures(1, &tex.id)
+		bindTexture(tex.id)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
+		gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+		gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+		gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(tex.width), int32(tex.height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(tex.snapshot))
+		bindTexture(0)
+
+		tex.snapshot = nil
+
+		if tex.mipmapped {
+			tex.GenerateMipmaps()
+			tex.SetLODBias(tex.lodBias)
+		}
+	}
+}
+
+// ResetShaders ... forget compiled shaders and tracked bindings, they belong to the old context
+func ResetShaders() {
+	storedShaders = nil
+	immediate = nil
+	resetBindings()
+}
+
+// Relink ... compile the program's retained shader sources and link them into a new program in the current context,
+// keeping its attributes and uniform values. Programs wrapped with CreateProgram have no sources so cannot be relinked.
+func (p *Program) Relink() error {
+	if len(p.sources) == 0 {
+		return fmt.Errorf("program %d has no retained shader sources to relink", p.Id)
+	}
+
+	p.Id = gl.CreateProgram()
+	var shaders []uint32
+
+	for shaderType, source := range p.sources {
+		s, err := tryCompileShader(source, shaderType)
+
+		if err != nil {
+			for _, compiled := range shaders {
+				gl.DeleteShader(compiled)
+			}
+
+			gl.DeleteProgram(p.Id)
+
+			return err
+		}
+
+		gl.AttachShader(p.Id, s)
+		shaders = append(shaders, s)
+	}
+
+	p.Link()
+
+	// The program keeps the compiled stages
+	for _, s := range shaders {
+		gl.DeleteShader(s)
+	}
+
+	if err := p.linkStatus(); err != nil {
+		return err
+	}
+
+	for name := range p.attributes {
+		p.AddAttribute(name)
+	}
+
+	p.Use()
+
+	for name, uni := range p.uniforms {
+		p.AddUniform(name, uni.value)
+	}
+
+	p.UnUse()
+
+	return nil
+}
+
+// RelinkPrograms ... relink every program linked from custom sources in the current context, before recreating vaos
+// which may share them. Programs which fail to relink are unregistered and the first error is returned.
+func RelinkPrograms() error {
+	var firstErr error
+	relinked := linkedPrograms[:0]
+
+	for _, program := range linkedPrograms {
+		if err := program.Relink(); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		relinked = append(relinked, program)
+	}
+
+	linkedPrograms = relinked
+
+	return firstErr
+}
+
+// Recreate ... recreate the vao in the current context from its cpu side data. The vao's program is relinked from
+// its retained sources keeping uniform values, unless it was already relinked by RelinkPrograms. A wrapped program
+// without sources, or one which fails to relink, is replaced by the default shader so the vao is always usable.
+func (vao *VAO) Recreate() error {
+	rot := vao.rot
+	rotGroups := make([]mgl32.Vec4, len(vao.rotGroups))
+	copy(rotGroups, vao.rotGroups)
+
+	gl.GenVertexArrays(1, &vao.ID)
+	gl.GenBuffers(1, &vao.vertID)
+	gl.GenBuffers(1, &vao.texID)
+	gl.GenBuffers(1, &vao.rotGroupID)
+	gl.GenBuffers(1, &vao.colourID)
+
+	var err error
+
+	// Registered programs are relinked once by RelinkPrograms
+	switch {
+	case len(vao.shader.sources) == 0:
+		vao.replaceWithDefaultShader()
+	case !isLinkedProgram(vao.shader):
+		if relinkErr := vao.shader.Relink(); relinkErr != nil {
+			err = fmt.Errorf("cannot relink vao shader program: %v", relinkErr)
+			vao.replaceWithDefaultShader()
+		}
+	}
+
+	vao.rot = rot
+
+	// Creating buffers resets grouped rotations
+	vao.created = false
+	vao.CreateBuffers()
+	vao.rotGroups = rotGroups
+	vao.UpdateBuffers()
+
+	return err
+}
+
+// replaceWithDefaultShader ... switch to the default shader keeping the values of uniforms it shares with the old program
+func (vao *VAO) replaceWithDefaultShader() {
+	values := make(map[string]interface{}, len(vao.shader.uniforms))
+
+	for name, uni := range vao.shader.uniforms {
+		values[name] = uni.value
+	}
+
+	vao.DefaultShader()
+
+	for name, value := range values {
+		if _, exists := vao.shader.uniforms[name]; exists {
+			vao.shader.SetUniform(name, value)
+		}
+	}
+}
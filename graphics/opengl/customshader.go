@@ -0,0 +1,179 @@
+package opengl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Partially custom shaders, a custom vertex or fragment stage paired with the default shader's other stage.
+The custom stage must declare the variables the default stages pass between each other.
+*/
+
+type declaration struct {
+	qualifier, typ, name string
+}
+
+var (
+	vertexDeclarations = []declaration{
+		{"in", "vec2", "vert"},
+		{"in", "vec2", "verttexcoord"},
+		{"out", "vec2", "fragtexcoord"},
+		{"out", "vec4", "fragcolour"},
+	}
+	fragmentDeclarations = []declaration{
+		{"in", "vec2", "fragtexcoord"},
+		{"in", "vec4", "fragcolour"},
+	}
+	// Attributes of the default vertex shader, custom vertex shaders may not use all of them
+	defaultAttributes = []string{"vert", "rotgroup", "verttexcoord", "vertcolour"}
+	// Programs linked from custom sources, registered so they can be prewarmed and relinked with a new context
+	linkedPrograms []*Program
+)
+
+// ShaderWithVertex ... link vertSrc with the default fragment shader. vertSrc must declare the vert and verttexcoord
+// attributes and the fragtexcoord and fragcolour outputs.
+func ShaderWithVertex(vertSrc string) (*Program, error) {
+	if err := checkDeclarations(vertSrc, vertexDeclarations); err != nil {
+		return nil, fmt.Errorf("invalid vertex shader: %v", err)
+	}
+
+	fragSrc, err := ReadFile(DEFAULT_FRAG_SHADER)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return linkSources(terminate(vertSrc), fragSrc)
+}
+
+// ShaderWithFragment ... link fragSrc with the default vertex shader. fragSrc must declare the fragtexcoord and
+// fragcolour inputs.
+func ShaderWithFragment(fragSrc string) (*Program, error) {
+	if err := checkDeclarations(fragSrc, fragmentDeclarations); err != nil {
+		return nil, fmt.Errorf("invalid fragment shader: %v", err)
+	}
+
+	vertSrc, err := ReadFile(DEFAULT_VERT_SHADER)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return linkSources(vertSrc, terminate(fragSrc))
+}
+
+func checkDeclarations(src string, declarations []declaration) error {
+	for _, d := range declarations {
+		pattern := regexp.MustCompile(`\b` + d.qualifier + `\s+` + d.typ + `\s+` + d.name + `\s*;`)
+
+		if !pattern.MatchString(src) {
+			return fmt.Errorf("missing declaration %s %s %s", d.qualifier, d.typ, d.name)
+		}
+	}
+
+	return nil
+}
+
+// terminate ... null terminate source for opengl
+func terminate(src string) string {
+	if strings.HasSuffix(src, "\x00") {
+		return src
+	}
+
+	return src + "\x00"
+}
+
+func linkSources(vertSrc, fragSrc string) (*Program, error) {
+	vert, err := tryCompileShader(vertSrc, VERTSHADER)
+
+	if err != nil {
+		return nil, err
+	}
+
+	frag, err := tryCompileShader(fragSrc, FRAGSHADER)
+
+	if err != nil {
+		gl.DeleteShader(vert)
+
+		return nil, err
+	}
+
+	program := CreateProgram(0)
+	program.sources[VERTSHADER] = vertSrc
+	program.sources[FRAGSHADER] = fragSrc
+	gl.AttachShader(program.Id, vert)
+	gl.AttachShader(program.Id, frag)
+	program.Link()
+
+	// The program keeps the compiled stages
+	gl.DeleteShader(vert)
+	gl.DeleteShader(frag)
+
+	if err := program.linkStatus(); err != nil {
+		return nil, err
+	}
+
+	for _, attribute := range defaultAttributes {
+		if gl.GetAttribLocation(program.Id, gl.Str(attribute+"\x00")) != -1 {
+			program.AddAttribute(attribute)
+		}
+	}
+
+	linkedPrograms = append(linkedPrograms, program)
+
+	return program, nil
+}
+
+// LinkedPrograms ... a snapshot of every program linked by ShaderWithVertex or ShaderWithFragment and not yet deleted
+func LinkedPrograms() []*Program {
+	programs := make([]*Program, len(linkedPrograms))
+	copy(programs, linkedPrograms)
+
+	return programs
+}
+
+// DeleteProgram ... delete a program linked by ShaderWithVertex or ShaderWithFragment, it must not be used afterwards
+func DeleteProgram(program *Program) {
+	gl.DeleteProgram(program.Id)
+
+	for i, p := range linkedPrograms {
+		if p == program {
+			linkedPrograms = append(linkedPrograms[:i], linkedPrograms[i+1:]...)
+
+			break
+		}
+	}
+}
+
+func isLinkedProgram(program *Program) bool {
+	for _, p := range linkedPrograms {
+		if p == program {
+			return true
+		}
+	}
+
+	return false
+}
+
+// linkStatus ... return the link log as an error and delete the program if linking failed
+func (program *Program) linkStatus() error {
+	var status int32
+	gl.GetProgramiv(program.Id, gl.LINK_STATUS, &status)
+
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetProgramiv(program.Id, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetProgramInfoLog(program.Id, logLength, nil, gl.Str(log))
+		gl.DeleteProgram(program.Id)
+
+		return fmt.Errorf("failed to link shader program: %v", log)
+	}
+
+	return nil
+}
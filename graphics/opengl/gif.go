@@ -0,0 +1,105 @@
+package opengl
+
+import (
+	"Gopengl/util"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"os"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Animated textures, every frame of a gif is decoded up front and uploaded over the texture as the animation advances.
+*/
+
+type AnimatedTexture struct {
+	*Texture
+	frames  [][]byte  // Fully composited rgba frames
+	delays  []float32 // Seconds each frame is shown for
+	frame   int
+	elapsed float32
+}
+
+// Gifs commonly use a delay of 0 expecting a sensible default
+const defaultGIFDelay = 0.1
+
+func TextureFromGIF(file string) (*AnimatedTexture, error) {
+	gifFile, err := os.Open(util.RelativePath(file))
+	if err != nil {
+		return nil, fmt.Errorf("gif %q not found on disk: %v", file, err)
+	}
+	defer gifFile.Close()
+
+	img, err := gif.DecodeAll(gifFile)
+	if err != nil {
+		return nil, fmt.Errorf("gif load error, error: %v", err)
+	}
+
+	if len(img.Image) == 0 {
+		return nil, fmt.Errorf("gif %q has no frames", file)
+	}
+
+	bounds := image.Rect(0, 0, img.Config.Width, img.Config.Height)
+	canvas := image.NewRGBA(bounds)
+	frames := make([][]byte, len(img.Image))
+	delays := make([]float32, len(img.Image))
+
+	for i, frame := range img.Image {
+		previous := make([]byte, len(canvas.Pix))
+		copy(previous, canvas.Pix)
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		frames[i] = make([]byte, len(canvas.Pix))
+		copy(frames[i], canvas.Pix)
+
+		delays[i] = float32(img.Delay[i]) / 100
+		if delays[i] <= 0 {
+			delays[i] = defaultGIFDelay
+		}
+
+		// Prepare the canvas for the next frame
+		if i < len(img.Disposal) {
+			switch img.Disposal[i] {
+			case gif.DisposalBackground:
+				draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			case gif.DisposalPrevious:
+				copy(canvas.Pix, previous)
+			}
+		}
+	}
+
+	texture := createTexture(file, bounds.Dx(), bounds.Dy(), gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE, frames[0])
+
+	return &AnimatedTexture{
+		texture,
+		frames,
+		delays,
+		0,
+		0,
+	}, nil
+}
+
+// Update ... advance the animation by dt seconds, uploading the new frame if it has changed
+func (a *AnimatedTexture) Update(dt float32) {
+	a.checkDeleted()
+
+	frame := a.frame
+	a.elapsed += dt
+
+	for a.elapsed >= a.delays[a.frame] {
+		a.elapsed -= a.delays[a.frame]
+		a.frame = (a.frame + 1) % len(a.frames)
+	}
+
+	if frame == a.frame {
+		return
+	}
+
+	bindTexture(a.id)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, 0, int32(a.width), int32(a.height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(a.frames[a.frame]))
+	bindTexture(0)
+}
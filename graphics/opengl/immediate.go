@@ -0,0 +1,92 @@
+package opengl
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Immediate drawing of untextured, single coloured geometry. Vertices are given in pixel coordinates and are uploaded
+every draw so this should only be used for small amounts of geometry (debug lines, stencil shapes, overlays).
+*/
+
+type immediateRenderer struct {
+	shader *Program
+	vaoID  uint32
+	vertID uint32
+}
+
+var immediate *immediateRenderer
+
+func createImmediateRenderer() *immediateRenderer {
+	var vaoID, vertID uint32
+
+	gl.GenVertexArrays(1, &vaoID)
+	gl.GenBuffers(1, &vertID)
+
+	program := CreateProgram(0)
+	program.LoadVertShader("./shaders/solid.vert")
+	program.LoadFragShader("./shaders/solid.frag")
+	program.Link()
+	program.AddAttribute("vert")
+
+	program.Use()
+	program.AddUniform("dim", mgl32.Vec2{})
+	program.AddUniform("colour", mgl32.Vec4{})
+	program.UnUse()
+
+	bindVertexArray(vaoID)
+	bindBuffer(gl.ARRAY_BUFFER, vertID)
+	vertAttrib := program.EnableAttribute("vert")
+	gl.VertexAttribPointer(vertAttrib, DEFAULT_VECTOR_SIZE, gl.FLOAT, false, 0, nil)
+	bindBuffer(gl.ARRAY_BUFFER, 0)
+	bindVertexArray(0)
+
+	return &immediateRenderer{
+		program,
+		vaoID,
+		vertID,
+	}
+}
+
+// PrewarmImmediate ... create the immediate renderer's shader program ahead of its first draw
+func PrewarmImmediate() {
+	if immediate == nil {
+		immediate = createImmediateRenderer()
+	}
+}
+
+// DrawImmediate ... draw verts with the given primitive mode (eg gl.TRIANGLE_FAN, gl.LINES) in a single colour.
+func DrawImmediate(mode uint32, verts []float32, colour mgl32.Vec4, width, height float32) {
+	if len(verts) < DEFAULT_VECTOR_SIZE {
+		return
+	}
+
+	if immediate == nil {
+		immediate = createImmediateRenderer()
+	}
+
+	immediate.shader.Use()
+	immediate.shader.SetUniform("dim", mgl32.Vec2{width, height})
+	immediate.shader.SetUniform("colour", colour)
+
+	bindVertexArray(immediate.vaoID)
+	bindBuffer(gl.ARRAY_BUFFER, immediate.vertID)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(verts), gl.Ptr(verts), gl.STREAM_DRAW)
+	gl.DrawArrays(mode, 0, int32(len(verts)/DEFAULT_VECTOR_SIZE))
+
+	bindBuffer(gl.ARRAY_BUFFER, 0)
+	bindVertexArray(0)
+	immediate.shader.UnUse()
+}
+
+func DeleteImmediate() {
+	if immediate == nil {
+		return
+	}
+
+	gl.DeleteBuffers(1, &immediate.vertID)
+	gl.DeleteVertexArrays(1, &immediate.vaoID)
+	gl.DeleteProgram(immediate.shader.Id)
+	immediate = nil
+}
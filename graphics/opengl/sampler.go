@@ -0,0 +1,119 @@
+package opengl
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Sampler objects, a sampler bound to a texture unit overrides the sampling parameters of whichever texture is bound
+to the unit so one configuration can be shared by many textures.
+*/
+
+// From GL_ARB_texture_filter_anisotropic, not part of the 4.1 core bindings
+const (
+	textureMaxAnisotropy    = 0x84FE
+	maxTextureMaxAnisotropy = 0x84FF
+)
+
+type Sampler struct {
+	id                   uint32
+	minFilter, magFilter int32
+	wrapS, wrapT         int32
+	anisotropy           float32
+}
+
+var storedSamplers []*Sampler
+
+// CreateSampler ... create a sampler with the same nearest filtering and edge clamping textures default to.
+// Sampler objects require opengl 3.3 or GL_ARB_sampler_objects.
+func CreateSampler() *Sampler {
+	s := &Sampler{0, gl.NEAREST, gl.NEAREST, gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE, 1}
+	s.create()
+
+	storedSamplers = append(storedSamplers, s)
+
+	return s
+}
+
+func (s *Sampler) create() {
+	gl.GenSamplers(1, &s.id)
+	gl.SamplerParameteri(s.id, gl.TEXTURE_MIN_FILTER, s.minFilter)
+	gl.SamplerParameteri(s.id, gl.TEXTURE_MAG_FILTER, s.magFilter)
+	gl.SamplerParameteri(s.id, gl.TEXTURE_WRAP_S, s.wrapS)
+	gl.SamplerParameteri(s.id, gl.TEXTURE_WRAP_T, s.wrapT)
+
+	if s.anisotropy > 1 {
+		gl.SamplerParameterf(s.id, textureMaxAnisotropy, s.anisotropy)
+	}
+}
+
+// SetFilter ... set the minification and magnification filters, eg gl.LINEAR or gl.NEAREST_MIPMAP_LINEAR
+func (s *Sampler) SetFilter(min, mag int32) {
+	gl.SamplerParameteri(s.id, gl.TEXTURE_MIN_FILTER, min)
+	gl.SamplerParameteri(s.id, gl.TEXTURE_MAG_FILTER, mag)
+
+	s.minFilter, s.magFilter = min, mag
+}
+
+// SetWrap ... set how texture coordinates outside of 0 to 1 are handled, eg gl.REPEAT or gl.CLAMP_TO_EDGE
+func (s *Sampler) SetWrap(wrapS, wrapT int32) {
+	gl.SamplerParameteri(s.id, gl.TEXTURE_WRAP_S, wrapS)
+	gl.SamplerParameteri(s.id, gl.TEXTURE_WRAP_T, wrapT)
+
+	s.wrapS, s.wrapT = wrapS, wrapT
+}
+
+// SetAnisotropy ... set the maximum anisotropic filtering level, clamped to the driver's maximum.
+// Returns false without changing the sampler if anisotropic filtering is unsupported.
+func (s *Sampler) SetAnisotropy(level float32) bool {
+	var maxLevel float32
+	gl.GetFloatv(maxTextureMaxAnisotropy, &maxLevel)
+
+	// The query fails leaving maxLevel as 0 without the extension
+	if maxLevel < 1 {
+		return false
+	}
+
+	if level > maxLevel {
+		level = maxLevel
+	}
+
+	gl.SamplerParameterf(s.id, textureMaxAnisotropy, level)
+	s.anisotropy = level
+
+	return true
+}
+
+// Bind ... use the sampler for the texture bound to unit, unit is an index such as 0 not gl.TEXTURE0
+func (s *Sampler) Bind(unit uint32) {
+	gl.BindSampler(unit, s.id)
+}
+
+// UnbindSampler ... return unit to sampling with its texture's own parameters
+func UnbindSampler(unit uint32) {
+	gl.BindSampler(unit, 0)
+}
+
+func (s *Sampler) ID() uint32 {
+	return s.id
+}
+
+// DeleteSampler ... delete the sampler from the gpu, it must not be used afterwards
+func DeleteSampler(s *Sampler) {
+	gl.DeleteSamplers(1, &s.id)
+
+	for i, sampler := range storedSamplers {
+		if sampler == s {
+			storedSamplers = append(storedSamplers[:i], storedSamplers[i+1:]...)
+
+			break
+		}
+	}
+}
+
+// RestoreSamplers ... recreate every sampler in the current context with its settings
+func RestoreSamplers() {
+	for _, s := range storedSamplers {
+		s.create()
+	}
+}
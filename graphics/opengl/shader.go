@@ -19,19 +19,27 @@ const (
 	GEOMSHADER = gl.GEOMETRY_SHADER
 )
 
+// Shaders used by render objects with the default shader
+const (
+	DEFAULT_VERT_SHADER = "./shaders/vertex.vert"
+	DEFAULT_FRAG_SHADER = "./shaders/fragment.frag"
+)
+
 var (
 	storedShaders []*shader
 )
 
 type shader struct {
-	Id   uint32
-	file string
+	Id     uint32
+	file   string
+	source string
 }
 
 type Program struct {
 	Id         uint32
 	attributes map[string]uint32
 	uniforms   map[string]uniform
+	sources    map[uint32]string // Shader type to source, retained so the program can be relinked in a new context
 }
 
 func CreateProgram(Id uint32) *Program {
@@ -43,6 +51,7 @@ func CreateProgram(Id uint32) *Program {
 		Id,
 		make(map[string]uint32),
 		make(map[string]uniform),
+		make(map[uint32]string),
 	}
 }
 
@@ -74,6 +83,7 @@ func (program *Program) LoadVertShader(source string) {
 
 	if existingShader != nil {
 		program.AttachShader(existingShader)
+		program.sources[VERTSHADER] = existingShader.source
 
 		return
 	}
@@ -84,7 +94,8 @@ func (program *Program) LoadVertShader(source string) {
 		panic(fmt.Errorf("Unable to find vertex shader file: %s, err: %s", source, err.Error()))
 	}
 
-	program.loadShader(rawData, VERTSHADER)
+	id := program.loadShader(rawData, VERTSHADER)
+	storedShaders = append(storedShaders, &shader{id, source, rawData})
 }
 
 func (program *Program) LoadFragShader(source string) {
@@ -92,6 +103,7 @@ func (program *Program) LoadFragShader(source string) {
 
 	if existingShader != nil {
 		program.AttachShader(existingShader)
+		program.sources[FRAGSHADER] = existingShader.source
 
 		return
 	}
@@ -102,10 +114,44 @@ func (program *Program) LoadFragShader(source string) {
 		panic(fmt.Errorf("Unable to find vertex shader file: %s", source))
 	}
 
-	program.loadShader(rawData, FRAGSHADER)
+	id := program.loadShader(rawData, FRAGSHADER)
+	storedShaders = append(storedShaders, &shader{id, source, rawData})
+}
+
+// PrecompileShader ... compile and store a shader without attaching it so later loads of the file reuse it
+func PrecompileShader(source string, shaderType uint32) {
+	if findShader(source) != nil {
+		return
+	}
+
+	rawData, err := ReadFile(source)
+
+	if err != nil {
+		panic(fmt.Errorf("Unable to find shader file: %s, err: %s", source, err.Error()))
+	}
+
+	storedShaders = append(storedShaders, &shader{compileShader(rawData, shaderType), source, rawData})
 }
 
-func (program *Program) loadShader(rawData string, shaderType uint32) {
+func (program *Program) loadShader(rawData string, shaderType uint32) uint32 {
+	shader := compileShader(rawData, shaderType)
+	gl.AttachShader(program.Id, shader)
+	program.sources[shaderType] = rawData
+
+	return shader
+}
+
+func compileShader(rawData string, shaderType uint32) uint32 {
+	shader, err := tryCompileShader(rawData, shaderType)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return shader
+}
+
+func tryCompileShader(rawData string, shaderType uint32) (uint32, error) {
 	shader := gl.CreateShader(shaderType)
 	source, free := gl.Strs(rawData)
 
@@ -122,10 +168,12 @@ func (program *Program) loadShader(rawData string, shaderType uint32) {
 
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
-		panic(fmt.Errorf("failed to compile %v: %v", source, log))
+		gl.DeleteShader(shader)
+
+		return 0, fmt.Errorf("failed to compile %v: %v", source, log)
 	}
 
-	gl.AttachShader(program.Id, shader)
+	return shader, nil
 }
 
 func findShader(file string) *shader {
@@ -143,11 +191,11 @@ Shader methods
 */
 
 func (p *Program) Use() {
-	gl.UseProgram(p.Id)
+	useProgram(p.Id)
 }
 
 func (p *Program) UnUse() {
-	gl.UseProgram(0)
+	useProgram(0)
 }
 
 func (p *Program) Link() {
@@ -195,6 +243,9 @@ func (uni *uniform) Value() interface{} {
 
 func (uni *uniform) Attach() {
 	switch uni.value.(type) {
+	case int32:
+		value := (uni.value).(int32)
+		gl.Uniform1i(int32(uni.id), value)
 	case float32:
 		value := (uni.value).(float32)
 		gl.Uniform1f(int32(uni.id), value)
@@ -207,6 +258,9 @@ func (uni *uniform) Attach() {
 	case mgl32.Vec4:
 		value := (uni.value).(mgl32.Vec4)
 		gl.Uniform4f(int32(uni.id), value.X(), value.Y(), value.Z(), value.W())
+	case mgl32.Mat4:
+		value := (uni.value).(mgl32.Mat4)
+		gl.UniformMatrix4fv(int32(uni.id), 1, false, &value[0])
 	default:
 		panic("Unsupported uniform type")
 	}
@@ -0,0 +1,96 @@
+package opengl
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+State change tracking, when enabled binding the program, vertex array, buffer or texture that was last bound is
+counted as redundant. Unbinding with 0 in between does not make a rebind necessary so is ignored. Every bind in this
+package goes through the functions below, binds made directly with gl elsewhere can skew the count.
+*/
+
+var (
+	trackState        = false
+	redundantChanges  = 0
+	lastProgram       uint32
+	lastVertexArray   uint32
+	lastBuffers       = make(map[uint32]uint32) // Buffer target to last bound buffer
+	lastTextures      = make(map[uint32]uint32) // Texture unit to last bound texture
+	activeTextureUnit = uint32(gl.TEXTURE0)
+)
+
+// SetStateTracking ... enable counting redundant state changes, disabled by default
+func SetStateTracking(enabled bool) {
+	trackState = enabled
+	ResetStateTracking()
+}
+
+// ResetStateTracking ... return the number of redundant state changes since the last reset and reset the count
+func ResetStateTracking() int {
+	changes := redundantChanges
+	redundantChanges = 0
+
+	return changes
+}
+
+// resetBindings ... forget every tracked binding, eg when the context is recreated
+func resetBindings() {
+	lastProgram = 0
+	lastVertexArray = 0
+	lastBuffers = make(map[uint32]uint32)
+	lastTextures = make(map[uint32]uint32)
+	activeTextureUnit = gl.TEXTURE0
+}
+
+func trackBind(last map[uint32]uint32, key, id uint32) {
+	if !trackState || id == 0 {
+		return
+	}
+
+	if last[key] == id {
+		redundantChanges++
+	}
+
+	last[key] = id
+}
+
+func useProgram(id uint32) {
+	gl.UseProgram(id)
+
+	if trackState && id != 0 {
+		if lastProgram == id {
+			redundantChanges++
+		}
+
+		lastProgram = id
+	}
+}
+
+func bindVertexArray(id uint32) {
+	gl.BindVertexArray(id)
+
+	if trackState && id != 0 {
+		if lastVertexArray == id {
+			redundantChanges++
+		}
+
+		lastVertexArray = id
+	}
+}
+
+func bindBuffer(target, id uint32) {
+	gl.BindBuffer(target, id)
+	trackBind(lastBuffers, target, id)
+}
+
+func activeTexture(unit uint32) {
+	gl.ActiveTexture(unit)
+	activeTextureUnit = unit
+}
+
+// bindTexture ... bind a 2d texture to the active texture unit
+func bindTexture(id uint32) {
+	gl.BindTexture(gl.TEXTURE_2D, id)
+	trackBind(lastTextures, activeTextureUnit, id)
+}
@@ -36,12 +36,22 @@ var (
 
 const textureIdsBeforeChange = 32
 
+// Texture unit reserved for palettes, after all units used by loaded textures
+const PALETTE_TEXTURE_UNIT = 15
+
 type Texture struct {
 	id          uint32
 	width       int
 	height      int
 	file        string
 	textureUnit uint32
+	mipmapped   bool
+	lodBias     float32
+	bytes       int    // Estimated gpu memory of the base level
+	lastUse     uint64 // Value of textureUses when the texture was last bound
+	snapshot    []byte // Pixels read back while the context is recreated
+	reloadable  bool   // Loaded by LoadTexture so can be loaded again from its file after being evicted
+	deleted     bool
 }
 
 /**
@@ -56,15 +66,61 @@ func LoadTexture(file string) *Texture {
 	}
 
 	// Create new texture if it doesn't exist
+	img, err := loadImage(file)
+	if err != nil {
+		panic(err)
+	}
+
+	texture := TextureFromImage(file, img)
+	texture.reloadable = true
+
+	return texture
+}
+
+/**
+Loads a texture from file stored under name instead of its file, returns an error if the file cannot be loaded.
+If a texture with the name already exists it is returned instead.
+*/
+func LoadTextureNamed(name, file string) (*Texture, error) {
+	existingTex := FindTex(name)
+
+	if existingTex != nil {
+		return existingTex, nil
+	}
+
+	img, err := loadImage(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return TextureFromImage(name, img), nil
+}
+
+func loadImage(file string) (image.Image, error) {
 	imgFile, err := os.Open(util.RelativePath(file))
 	if err != nil {
-		panic(fmt.Errorf("texture %q not found on disk: %v", file, err))
+		return nil, fmt.Errorf("texture %q not found on disk: %v", file, err)
 	}
+	defer imgFile.Close()
 
 	// Get imagine data
 	img, _, err := image.Decode(imgFile)
 	if err != nil {
-		panic(fmt.Errorf("Image load error, error: %v", err))
+		return nil, fmt.Errorf("Image load error, error: %v", err)
+	}
+
+	return img, nil
+}
+
+/**
+Creates a texture from an image already in memory, name is used in place of a file to find the texture later.
+If a texture with the name already exists it is returned instead.
+*/
+func TextureFromImage(name string, img image.Image) *Texture {
+	existingTex := FindTex(name)
+
+	if existingTex != nil {
+		return existingTex
 	}
 
 	bounds := img.Bounds()
@@ -74,34 +130,94 @@ func LoadTexture(file string) *Texture {
 	}
 	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
 
+	return createTexture(name, bounds.Max.X, bounds.Max.Y, gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE, rgba.Pix)
+}
+
+/**
+Creates a texture from raw pixel data with explicit formats, eg gl.R8 with gl.RED for a single channel mask or
+gl.RGBA16F with gl.RGBA and gl.HALF_FLOAT for HDR data. The length of pixels must match w, h, format and typ.
+*/
+func TextureFromBytesFormat(pixels []byte, w, h int, internalFormat, format, typ uint32) (*Texture, error) {
+	components, validFormat := formatComponents[format]
+	size, validType := typeSizes[typ]
+
+	if !validFormat || !validType {
+		return nil, fmt.Errorf("unsupported texture format %d or type %d", format, typ)
+	}
+
+	if expected := w * h * components * size; len(pixels) != expected {
+		return nil, fmt.Errorf("texture data is %d bytes, expected %d for %d by %d pixels", len(pixels), expected, w, h)
+	}
+
+	bytesTextures++
+
+	return createTexture(fmt.Sprintf("bytes:%d", bytesTextures), w, h, internalFormat, format, typ, pixels), nil
+}
+
+// CreateEmptyTexture ... create a transparent rgba texture, eg to render into. Returns the existing texture if name is already used
+func CreateEmptyTexture(name string, w, h int) *Texture {
+	existingTex := FindTex(name)
+
+	if existingTex != nil {
+		return existingTex
+	}
+
+	return createTexture(name, w, h, gl.RGBA8, gl.RGBA, gl.UNSIGNED_BYTE, make([]byte, w*h*4))
+}
+
+var (
+	formatComponents = map[uint32]int{
+		gl.RED:  1,
+		gl.RG:   2,
+		gl.RGB:  3,
+		gl.RGBA: 4,
+	}
+	typeSizes = map[uint32]int{
+		gl.UNSIGNED_BYTE: 1,
+		gl.HALF_FLOAT:    2,
+		gl.FLOAT:         4,
+	}
+	bytesTextures = 0
+)
+
+func createTexture(name string, w, h int, internalFormat, format, typ uint32, pixels []byte) *Texture {
 	var texture uint32
-	gl.ActiveTexture(currentTextureUnit())
+	activeTexture(currentTextureUnit())
 	gl.GenTextures(1, &texture)
-	gl.BindTexture(gl.TEXTURE_2D, texture)
+	bindTexture(texture)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
+	// Rows of single and three channel data are not always 4 byte aligned
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
 	gl.TexImage2D(
 		gl.TEXTURE_2D,
 		0,
-		gl.RGBA,
-		int32(rgba.Rect.Size().X),
-		int32(rgba.Rect.Size().Y),
+		int32(internalFormat),
+		int32(w),
+		int32(h),
 		0,
-		gl.RGBA,
-		gl.UNSIGNED_BYTE,
-		gl.Ptr(rgba.Pix))
+		format,
+		typ,
+		gl.Ptr(pixels))
 
 	textureObj := &Texture{
 		texture,
-		bounds.Max.X,
-		bounds.Max.Y,
-		file,
+		w,
+		h,
+		name,
 		currentTextureUnitId,
+		false,
+		0,
+		w * h * internalFormatSize(internalFormat),
+		textureUses,
+		nil,
+		false,
+		false,
 	}
 
-	gl.BindTexture(gl.TEXTURE_2D, 0)
+	bindTexture(0)
 
 	//Add texture to texture store
 	storedTextures = append(storedTextures, textureObj)
@@ -119,13 +235,107 @@ func FindTex(file string) *Texture {
 	return nil
 }
 
+func (t *Texture) Width() int {
+	return t.width
+}
+
+func (t *Texture) Height() int {
+	return t.height
+}
+
+// Pixels ... read the texture back from the gpu as rgba bytes, row by row from the top left
+func (t *Texture) Pixels() []byte {
+	pixels := make([]byte, t.width*t.height*4)
+
+	bindTexture(t.id)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.GetTexImage(gl.TEXTURE_2D, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+	bindTexture(0)
+
+	return pixels
+}
+
+// ID ... the opengl texture name, eg for attaching to a framebuffer
+func (t *Texture) ID() uint32 {
+	return t.id
+}
+
+// File ... the source file the texture was loaded from, also used as its key in the texture store
+func (t *Texture) File() string {
+	return t.file
+}
+
 /*
 Texture usage methods
 */
 
 func (t *Texture) Use() {
-	gl.ActiveTexture(t.textureUnit)
-	gl.BindTexture(gl.TEXTURE_2D, t.id)
+	t.checkDeleted()
+	activeTexture(textureUnits[t.textureUnit])
+	bindTexture(t.id)
+	t.touch()
+}
+
+// UseUnit ... bind the texture to a specific texture unit instead of its own
+func (t *Texture) UseUnit(unit uint32) {
+	t.checkDeleted()
+	activeTexture(gl.TEXTURE0 + unit)
+	bindTexture(t.id)
+	t.touch()
+}
+
+// Unit ... index of the texture unit the texture is bound to by Use
+func (t *Texture) Unit() uint32 {
+	return t.textureUnit
+}
+
+// GenerateMipmaps ... generate mipmaps for the texture and switch minification to use them
+func (t *Texture) GenerateMipmaps() {
+	bindTexture(t.id)
+	gl.GenerateMipmap(gl.TEXTURE_2D)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST_MIPMAP_LINEAR)
+	bindTexture(0)
+
+	t.mipmapped = true
+}
+
+// SetLODBias ... offset the mipmap level selected when sampling, negative values sharpen and positive values soften.
+func (t *Texture) SetLODBias(bias float32) {
+	if !t.mipmapped {
+		panic(fmt.Errorf("cannot set LOD bias of texture %s without mipmaps", t.file))
+	}
+
+	bindTexture(t.id)
+	gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_LOD_BIAS, bias)
+	bindTexture(0)
+
+	t.lodBias = bias
+}
+
+var swizzleSources = map[int32]bool{
+	gl.RED:   true,
+	gl.GREEN: true,
+	gl.BLUE:  true,
+	gl.ALPHA: true,
+	gl.ZERO:  true,
+	gl.ONE:   true,
+}
+
+// SetSwizzle ... choose the source of each channel when sampled, each of gl.RED, gl.GREEN, gl.BLUE, gl.ALPHA,
+// gl.ZERO or gl.ONE. For instance gl.ONE, gl.ONE, gl.ONE, gl.RED draws a single channel mask as white with alpha.
+// Defaults to gl.RED, gl.GREEN, gl.BLUE, gl.ALPHA.
+func (t *Texture) SetSwizzle(r, g, b, a int32) {
+	swizzle := []int32{r, g, b, a}
+
+	for _, source := range swizzle {
+		if !swizzleSources[source] {
+			panic(fmt.Errorf("invalid swizzle source %d for texture %s", source, t.file))
+		}
+	}
+
+	bindTexture(t.id)
+	gl.TexParameteriv(gl.TEXTURE_2D, gl.TEXTURE_SWIZZLE_RGBA, &swizzle[0])
+	bindTexture(0)
 }
 
 // NormCoords ... normalize pixture texture coordinates
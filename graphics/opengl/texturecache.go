@@ -0,0 +1,122 @@
+package opengl
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Texture cache, every created texture is kept in storedTextures until deleted. Memory use is an estimate from the
+internal format and dimensions, drivers may pad or compress textures.
+*/
+
+// Incremented every bind, orders textures by their last use
+var textureUses uint64 = 0
+
+var internalFormatSizes = map[uint32]int{
+	gl.R8:      1,
+	gl.RG8:     2,
+	gl.RGB8:    3,
+	gl.RGBA8:   4,
+	gl.R16F:    2,
+	gl.RG16F:   4,
+	gl.RGB16F:  6,
+	gl.RGBA16F: 8,
+	gl.R32F:    4,
+	gl.RG32F:   8,
+	gl.RGB32F:  12,
+	gl.RGBA32F: 16,
+}
+
+func internalFormatSize(internalFormat uint32) int {
+	if size, exists := internalFormatSizes[internalFormat]; exists {
+		return size
+	}
+
+	return 4
+}
+
+func (t *Texture) checkDeleted() {
+	if t.deleted {
+		panic(fmt.Errorf("texture %s has been deleted, evicted textures must be loaded again", t.file))
+	}
+}
+
+func (t *Texture) touch() {
+	textureUses++
+	t.lastUse = textureUses
+}
+
+// Bytes ... estimated gpu memory used by the texture including mipmaps
+func (t *Texture) Bytes() int {
+	if t.mipmapped {
+		// A full mip chain adds a third
+		return t.bytes * 4 / 3
+	}
+
+	return t.bytes
+}
+
+// TextureCacheBytes ... estimated gpu memory used by all stored textures
+func TextureCacheBytes() int {
+	total := 0
+
+	for _, tex := range storedTextures {
+		total += tex.Bytes()
+	}
+
+	return total
+}
+
+// EvictTextures ... delete least recently used textures not in keep until the cache is within budget bytes.
+// Only textures loaded from a file by LoadTexture are evicted, others cannot be reloaded.
+// Returns the number of textures deleted.
+func EvictTextures(budget int, keep map[*Texture]bool) int {
+	total := TextureCacheBytes()
+
+	if total <= budget {
+		return 0
+	}
+
+	candidates := make([]*Texture, 0, len(storedTextures))
+
+	for _, tex := range storedTextures {
+		if tex.reloadable && !keep[tex] {
+			candidates = append(candidates, tex)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].lastUse < candidates[j].lastUse
+	})
+
+	evicted := 0
+
+	for _, tex := range candidates {
+		if total <= budget {
+			break
+		}
+
+		total -= tex.Bytes()
+		DeleteTexture(tex)
+		evicted++
+	}
+
+	return evicted
+}
+
+// DeleteTexture ... delete the texture from the gpu and the texture store, using it afterwards panics
+func DeleteTexture(t *Texture) {
+	gl.DeleteTextures(1, &t.id)
+	t.deleted = true
+
+	for i, tex := range storedTextures {
+		if tex == t {
+			storedTextures = append(storedTextures[:i], storedTextures[i+1:]...)
+
+			break
+		}
+	}
+}
@@ -17,16 +17,19 @@ import (
 
 const DEFAULT_VECTOR_SIZE = 2
 const DEFAULT_TEXS_SIZE = 2
+const DEFAULT_COLOUR_SIZE = 4
 
 type VAO struct {
 	ID                        uint32
 	vertID                    uint32
 	texID                     uint32
 	rotGroupID                uint32
+	colourID                  uint32
 	windowWidth, windowHeight float32
 	verts                     []float32
 	texs                      []float32
 	rotGroups                 []mgl32.Vec4 // Grouped rotations
+	colours                   []float32    // Per vertex colour the texture is multiplied by
 	rot                       mgl32.Vec4   // Global VAO rotation
 	trans                     mgl32.Vec2   // Global VAO translation, individual translation should be performed on each vertex
 	vertNum                   int32
@@ -36,6 +39,8 @@ type VAO struct {
 	uniforms                  map[string]interface{}
 	cam                       mgl32.Vec2
 	zoom                      float32
+	palette                   *Texture
+	samplers                  map[uint32]*Sampler // Samplers bound to texture units while rendering
 }
 
 /*
@@ -44,12 +49,13 @@ VBO creation and modification functions
 
 //CreateVAO ... size of vao in vertices.
 func CreateVAO(size uint32, textureSource string, defaultShader bool, width float32, height float32) *VAO {
-	var vaoID, vertID, rotGroupID, texID uint32
+	var vaoID, vertID, rotGroupID, texID, colourID uint32
 
 	gl.GenVertexArrays(1, &vaoID)
 	gl.GenBuffers(1, &vertID)
 	gl.GenBuffers(1, &texID)
 	gl.GenBuffers(1, &rotGroupID)
+	gl.GenBuffers(1, &colourID)
 
 	var program *Program
 
@@ -59,11 +65,13 @@ func CreateVAO(size uint32, textureSource string, defaultShader bool, width floa
 		vertID,
 		texID,
 		rotGroupID,
+		colourID,
 		width,
 		height,
 		make([]float32, size*DEFAULT_VECTOR_SIZE),
 		make([]float32, size*DEFAULT_TEXS_SIZE),
 		make([]mgl32.Vec4, size),
+		whiteColours(size),
 		mgl32.Vec4{},
 		mgl32.Vec2{},
 		int32(size),
@@ -74,6 +82,8 @@ func CreateVAO(size uint32, textureSource string, defaultShader bool, width floa
 		make(map[string]interface{}),
 		mgl32.Vec2{},
 		1,
+		nil,
+		make(map[uint32]*Sampler),
 	}
 
 	vao.DefaultShader()
@@ -92,30 +102,54 @@ func (vao *VAO) CreateBuffers() {
 
 	vao.created = true
 
-	gl.BindVertexArray(vao.ID)
+	bindVertexArray(vao.ID)
 
 	//vertex buffer
-	gl.BindBuffer(gl.ARRAY_BUFFER, vao.vertID)
+	bindBuffer(gl.ARRAY_BUFFER, vao.vertID)
 	gl.BufferData(gl.ARRAY_BUFFER, 4*len(vao.verts), gl.Ptr(vao.verts), gl.DYNAMIC_DRAW)
-	vertAttrib := vao.shader.EnableAttribute("vert")
-	gl.VertexAttribPointer(vertAttrib, DEFAULT_VECTOR_SIZE, gl.FLOAT, false, 0, nil)
 
 	//grouped rotation buffer
-	gl.BindBuffer(gl.ARRAY_BUFFER, vao.rotGroupID)
+	bindBuffer(gl.ARRAY_BUFFER, vao.rotGroupID)
 	vao.ResetGroupedRotation()
 	rotGroups := destructureVecArray(vao.rotGroups)
 	gl.BufferData(gl.ARRAY_BUFFER, 4*len(rotGroups), gl.Ptr(rotGroups), gl.DYNAMIC_DRAW)
-	rotGroupAttrib := vao.shader.EnableAttribute("rotgroup")
-	gl.VertexAttribPointer(rotGroupAttrib, 4, gl.FLOAT, false, 0, nil)
 
 	//texture buffer
-	gl.BindBuffer(gl.ARRAY_BUFFER, vao.texID)
+	bindBuffer(gl.ARRAY_BUFFER, vao.texID)
 	gl.BufferData(gl.ARRAY_BUFFER, 4*len(vao.texs), gl.Ptr(vao.texs), gl.DYNAMIC_DRAW)
-	texAttrib := vao.shader.EnableAttribute("verttexcoord")
-	gl.VertexAttribPointer(texAttrib, DEFAULT_TEXS_SIZE, gl.FLOAT, false, 0, nil)
 
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
-	gl.BindVertexArray(0)
+	//colour buffer
+	bindBuffer(gl.ARRAY_BUFFER, vao.colourID)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(vao.colours), gl.Ptr(vao.colours), gl.DYNAMIC_DRAW)
+
+	bindBuffer(gl.ARRAY_BUFFER, 0)
+	bindVertexArray(0)
+
+	vao.bindAttributes()
+}
+
+// bindAttributes ... point the shader's attributes at the vao's buffers, attributes the shader does not declare are
+// skipped so they cannot take over another attribute's location
+func (vao *VAO) bindAttributes() {
+	bindVertexArray(vao.ID)
+
+	vao.bindAttribute("vert", vao.vertID, DEFAULT_VECTOR_SIZE)
+	vao.bindAttribute("rotgroup", vao.rotGroupID, 4)
+	vao.bindAttribute("verttexcoord", vao.texID, DEFAULT_TEXS_SIZE)
+	vao.bindAttribute("vertcolour", vao.colourID, DEFAULT_COLOUR_SIZE)
+
+	bindBuffer(gl.ARRAY_BUFFER, 0)
+	bindVertexArray(0)
+}
+
+func (vao *VAO) bindAttribute(name string, buffer uint32, size int32) {
+	if _, exists := vao.shader.attributes[name]; !exists {
+		return
+	}
+
+	bindBuffer(gl.ARRAY_BUFFER, buffer)
+	attrib := vao.shader.EnableAttribute(name)
+	gl.VertexAttribPointer(attrib, size, gl.FLOAT, false, 0, nil)
 }
 
 func (vao *VAO) UpdateBuffers() {
@@ -125,22 +159,26 @@ func (vao *VAO) UpdateBuffers() {
 		return
 	}
 
-	gl.BindVertexArray(vao.ID)
+	bindVertexArray(vao.ID)
 	// Verts
-	gl.BindBuffer(gl.ARRAY_BUFFER, vao.vertID)
+	bindBuffer(gl.ARRAY_BUFFER, vao.vertID)
 	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(vao.verts), gl.Ptr(vao.verts))
 
 	//Grouped rotations
-	gl.BindBuffer(gl.ARRAY_BUFFER, vao.rotGroupID)
+	bindBuffer(gl.ARRAY_BUFFER, vao.rotGroupID)
 	rotGroups := destructureVecArray(vao.rotGroups)
 	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(rotGroups), gl.Ptr(rotGroups))
 
 	// Texs
-	gl.BindBuffer(gl.ARRAY_BUFFER, vao.texID)
+	bindBuffer(gl.ARRAY_BUFFER, vao.texID)
 	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(vao.texs), gl.Ptr(vao.texs))
 
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
-	gl.BindVertexArray(0)
+	// Colours
+	bindBuffer(gl.ARRAY_BUFFER, vao.colourID)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, 4*len(vao.colours), gl.Ptr(vao.colours))
+
+	bindBuffer(gl.ARRAY_BUFFER, 0)
+	bindVertexArray(0)
 }
 
 func (vao *VAO) UpdateBufferIndex(index int, vert_data []float32, tex_data []float32) {
@@ -195,6 +233,68 @@ func (vao *VAO) UpdateTexBufferIndex(index int, texData []float32) {
 	vao.UpdateBuffers()
 }
 
+// UpdateTexBufferRange ... set the texture coordinates from index, only uploading that range of the buffer
+func (vao *VAO) UpdateTexBufferRange(index int, texData []float32) {
+	if !vao.created {
+		vao.CreateBuffers()
+	}
+
+	offset := index * DEFAULT_TEXS_SIZE
+	copy(vao.texs[offset:], texData)
+
+	bindBuffer(gl.ARRAY_BUFFER, vao.texID)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 4*offset, 4*len(texData), gl.Ptr(texData))
+	bindBuffer(gl.ARRAY_BUFFER, 0)
+}
+
+func (vao *VAO) UpdateColourBufferIndex(index int, colourData []float32) {
+	index *= DEFAULT_COLOUR_SIZE
+
+	for i, val := range colourData {
+		vao.colours[index+i] = val
+	}
+
+	vao.UpdateBuffers()
+}
+
+func (vao *VAO) UpdateRotGroupBufferIndex(index int, rotGroupData []mgl32.Vec4) {
+	for i, val := range rotGroupData {
+		vao.rotGroups[index+i] = val
+	}
+
+	vao.UpdateBuffers()
+}
+
+// CopyVertices ... copy count vertices of every buffer from src to dst, does not update the buffer
+func (vao *VAO) CopyVertices(dst, src, count int) {
+	copy(vao.verts[dst*DEFAULT_VECTOR_SIZE:], vao.verts[src*DEFAULT_VECTOR_SIZE:(src+count)*DEFAULT_VECTOR_SIZE])
+	copy(vao.texs[dst*DEFAULT_TEXS_SIZE:], vao.texs[src*DEFAULT_TEXS_SIZE:(src+count)*DEFAULT_TEXS_SIZE])
+	copy(vao.colours[dst*DEFAULT_COLOUR_SIZE:], vao.colours[src*DEFAULT_COLOUR_SIZE:(src+count)*DEFAULT_COLOUR_SIZE])
+	copy(vao.rotGroups[dst:], vao.rotGroups[src:src+count])
+}
+
+// ClearVertices ... reset count vertices from index to their initial values, does not update the buffer
+func (vao *VAO) ClearVertices(index, count int) {
+	for i := index; i < index+count; i++ {
+		vao.verts[i*DEFAULT_VECTOR_SIZE] = 0
+		vao.verts[i*DEFAULT_VECTOR_SIZE+1] = 0
+		vao.texs[i*DEFAULT_TEXS_SIZE] = 0
+		vao.texs[i*DEFAULT_TEXS_SIZE+1] = 0
+		vao.rotGroups[i] = mgl32.Vec4{0, 0, 1, 0}
+
+		for c := 0; c < DEFAULT_COLOUR_SIZE; c++ {
+			vao.colours[i*DEFAULT_COLOUR_SIZE+c] = 1
+		}
+	}
+}
+
+// SetVertices ... set the vertex, texture coordinate and colour data from index, does not update the buffer
+func (vao *VAO) SetVertices(index int, vertData, texData, colourData []float32) {
+	copy(vao.verts[index*DEFAULT_VECTOR_SIZE:], vertData)
+	copy(vao.texs[index*DEFAULT_TEXS_SIZE:], texData)
+	copy(vao.colours[index*DEFAULT_COLOUR_SIZE:], colourData)
+}
+
 // SetData ... set the vert/tex data of the vao, does not update the buffer
 func (vao *VAO) SetData(vertData []float32, texData []float32, rotGroupData []mgl32.Vec4) {
 	vao.verts = vertData
@@ -202,6 +302,54 @@ func (vao *VAO) SetData(vertData []float32, texData []float32, rotGroupData []mg
 	vao.rotGroups = rotGroupData
 }
 
+/*
+Buffer data accessors, these return the VAO struct buffers not copies so must not be modified directly
+*/
+
+func (vao *VAO) Verts() []float32 {
+	return vao.verts
+}
+
+func (vao *VAO) Texs() []float32 {
+	return vao.texs
+}
+
+func (vao *VAO) RotGroups() []mgl32.Vec4 {
+	return vao.rotGroups
+}
+
+func (vao *VAO) Colours() []float32 {
+	return vao.colours
+}
+
+func (vao *VAO) Palette() *Texture {
+	return vao.palette
+}
+
+func (vao *VAO) UsesDefaultShader() bool {
+	return vao.defaultShader
+}
+
+func (vao *VAO) Shader() *Program {
+	return vao.shader
+}
+
+// Samplers ... texture unit to the sampler overriding it while rendering
+func (vao *VAO) Samplers() map[uint32]*Sampler {
+	return vao.samplers
+}
+
+// Uniform ... the current value of the shader's uniform name, nil if the shader has no such uniform
+func (vao *VAO) Uniform(name string) interface{} {
+	uni, exists := vao.shader.uniforms[name]
+
+	if !exists {
+		return nil
+	}
+
+	return uni.value
+}
+
 /*
 Global rotation
 */
@@ -249,9 +397,118 @@ func (vao *VAO) SetZoom(z float32) {
 	vao.shader.SetUniform("zoom", vao.zoom)
 }
 
+// SetProjection ... set the projection from pixel coordinates to clip space
+func (vao *VAO) SetProjection(proj mgl32.Mat4) {
+	vao.shader.SetUniform("proj", proj)
+}
+
+// SetModel ... set the model transform applied to every vertex in pixel coordinates before projection
+func (vao *VAO) SetModel(model mgl32.Mat4) {
+	vao.shader.SetUniform("model", model)
+}
+
+// SetDepth ... set the depth of every vertex, must be within the projection's depth range to be visible
+func (vao *VAO) SetDepth(depth float32) {
+	vao.shader.SetUniform("depth", depth)
+}
+
+// SetPalette ... treat the red channel of the texture as an index into the first row of palette, nil disables palettes
+func (vao *VAO) SetPalette(palette *Texture) {
+	vao.palette = palette
+
+	if palette == nil {
+		vao.shader.SetUniform("paletted", float32(0))
+
+		return
+	}
+
+	vao.shader.SetUniform("paletted", float32(1))
+}
+
+// SetSampler ... sample the texture bound to unit using s while rendering, pass nil to use the texture's own parameters
+func (vao *VAO) SetSampler(unit uint32, s *Sampler) {
+	if s == nil {
+		delete(vao.samplers, unit)
+
+		return
+	}
+
+	vao.samplers[unit] = s
+}
+
+// SetColourMod ... multiply every fragment's colour by mul then add add
+func (vao *VAO) SetColourMod(mul, add mgl32.Vec4) {
+	vao.shader.SetUniform("colourmul", mul)
+	vao.shader.SetUniform("colouradd", add)
+}
+
+// SetAlphaThreshold ... discard fragments with an alpha below threshold
+func (vao *VAO) SetAlphaThreshold(threshold float32) {
+	vao.shader.SetUniform("alphathreshold", threshold)
+}
+
+// SetScale ... scale every vertex about x, y, applied before any rotation
+func (vao *VAO) SetScale(x, y, scaleX, scaleY float32) {
+	vao.shader.SetUniform("scale", mgl32.Vec4{x, y, scaleX, scaleY})
+}
+
+// SetFill ... draw every fragment in a solid colour instead of the texture colour, keeping the texture alpha
+func (vao *VAO) SetFill(colour mgl32.Vec4, filled bool) {
+	var fill float32 = 0
+
+	if filled {
+		fill = 1
+	}
+
+	vao.shader.SetUniform("fill", colour)
+	vao.shader.SetUniform("filled", fill)
+}
+
+// TransformedVerts ... a copy of the first count vertices with the current scale, grouped rotations and global rotation
+// followed by a translation of x, y pixels applied, matching the default shader's transformations.
+func (vao *VAO) TransformedVerts(count int, x, y float32) []float32 {
+	scale := mgl32.Vec4{0, 0, 1, 1}
+
+	// Custom shaders may not be scaled
+	if uni, exists := vao.shader.uniforms["scale"]; exists {
+		scale = uni.value.(mgl32.Vec4)
+	}
+
+	verts := make([]float32, count*DEFAULT_VECTOR_SIZE)
+
+	for i, rotGroup := range vao.rotGroups[:count] {
+		pos := mgl32.Vec2{vao.verts[i*DEFAULT_VECTOR_SIZE], vao.verts[i*DEFAULT_VECTOR_SIZE+1]}
+
+		pos = mgl32.Vec2{
+			(pos.X()-scale.X())*scale.Z() + scale.X(),
+			(pos.Y()-scale.Y())*scale.W() + scale.Y(),
+		}
+		pos = rotateAbout(pos, rotGroup)
+		pos = rotateAbout(pos, vao.rot)
+
+		verts[i*DEFAULT_VECTOR_SIZE] = pos.X() + x
+		verts[i*DEFAULT_VECTOR_SIZE+1] = pos.Y() + y
+	}
+
+	return verts
+}
+
+// BakeTransform ... apply the current scale, grouped rotations and global rotation followed by a translation of x, y
+// pixels directly to the vertices then reset them, matching the default shader's transformations.
+func (vao *VAO) BakeTransform(x, y float32) {
+	copy(vao.verts, vao.TransformedVerts(len(vao.rotGroups), x, y))
+
+	vao.SetScale(0, 0, 1, 1)
+	vao.ResetGroupedRotation()
+	vao.SetRotation(0, 0, 0)
+	vao.UpdateBuffers()
+}
+
 func (vao *VAO) Delete() {
 	gl.DeleteBuffers(1, &vao.vertID)
 	gl.DeleteBuffers(1, &vao.texID)
+	gl.DeleteBuffers(1, &vao.rotGroupID)
+	gl.DeleteBuffers(1, &vao.colourID)
 	gl.DeleteVertexArrays(1, &vao.ID)
 }
 
@@ -261,15 +518,27 @@ Render handling
 
 func (vao *VAO) PrepRender() int32 {
 	vao.shader.Use()
+	vao.shader.SetUniform("tex", int32(vao.Texture.Unit()))
 	vao.PrepUniforms()
-	gl.BindVertexArray(vao.ID)
+	bindVertexArray(vao.ID)
 	vao.Texture.Use()
 
+	if vao.palette != nil {
+		vao.palette.UseUnit(PALETTE_TEXTURE_UNIT)
+	}
+
+	for unit, sampler := range vao.samplers {
+		sampler.Bind(unit)
+	}
+
 	return vao.vertNum
 }
 
 func (vao *VAO) FinishRender() {
-	// TODO: figure out if this needs removing at some point
+	// Samplers override every texture bound to their unit so must not outlive the vao's render
+	for unit := range vao.samplers {
+		UnbindSampler(unit)
+	}
 }
 
 /*
@@ -280,34 +549,96 @@ func (vao *VAO) DefaultShader() Program {
 	program := CreateProgram(0)
 	vao.AttachProgram(program)
 
-	program.LoadVertShader("./shaders/vertex.vert")
-	program.LoadFragShader("./shaders/fragment.frag")
+	program.LoadVertShader(DEFAULT_VERT_SHADER)
+	program.LoadFragShader(DEFAULT_FRAG_SHADER)
 	program.Link()
 
 	program.AddAttribute("vert")
 	// Currently unusued, optimized out by the shader compiler so will fail
 	program.AddAttribute("rotgroup")
 	program.AddAttribute("verttexcoord")
+	program.AddAttribute("vertcolour")
+
+	for name, value := range vao.defaultUniforms() {
+		vao.AddUniform(name, value)
+	}
 
-	// Add and set rotation uniform
-	vao.AddUniform("rot", mgl32.Vec4{})
+	// Set rotation uniform, other uniforms can use default values.
 	vao.SetRotation(0, 0, 0)
 
-	// Other uniforms can use default values.
-	var zoom float32 = 1.5
+	return *program
+}
 
-	vao.AddUniform("trans", mgl32.Vec2{})
-	vao.AddUniform("dim", mgl32.Vec2{vao.windowWidth, vao.windowHeight})
-	vao.AddUniform("cam", mgl32.Vec2{})
-	vao.AddUniform("zoom", zoom)
+// defaultUniforms ... the default shader's uniforms and their initial values
+func (vao *VAO) defaultUniforms() map[string]interface{} {
+	var zoom float32 = 1.5
 
-	return *program
+	return map[string]interface{}{
+		"rot":            mgl32.Vec4{},
+		"trans":          mgl32.Vec2{},
+		"dim":            mgl32.Vec2{vao.windowWidth, vao.windowHeight},
+		"cam":            mgl32.Vec2{},
+		"zoom":           zoom,
+		"scale":          mgl32.Vec4{0, 0, 1, 1},
+		"proj":           mgl32.Ortho(0, vao.windowWidth, vao.windowHeight, 0, -1, 1),
+		"depth":          float32(0),
+		"model":          mgl32.Ident4(),
+		"tex":            int32(0),
+		"palette":        int32(PALETTE_TEXTURE_UNIT),
+		"paletted":       float32(0),
+		"alphathreshold": float32(0),
+		"colourmul":      mgl32.Vec4{1, 1, 1, 1},
+		"colouradd":      mgl32.Vec4{},
+		"fill":           mgl32.Vec4{},
+		"filled":         float32(0),
+	}
 }
 
 func (vao *VAO) AttachProgram(program *Program) {
 	vao.shader = program
 }
 
+// SetShader ... draw with program, eg from ShaderWithVertex or ShaderWithFragment, in place of the current shader.
+// The default shader's uniforms are registered on program keeping the vao's current values, uniforms program does not
+// declare are ignored by opengl. The vao's buffers are rebound to program's attribute locations. Outlines are not
+// drawn for vaos using a custom shader.
+func (vao *VAO) SetShader(program *Program) {
+	old := vao.shader
+	values := vao.defaultUniforms()
+
+	for name, uni := range old.uniforms {
+		values[name] = uni.value
+	}
+
+	program.Use()
+
+	for name, value := range values {
+		if _, exists := program.uniforms[name]; !exists {
+			program.AddUniform(name, value)
+		}
+	}
+
+	program.UnUse()
+
+	// Attributes of the old program would otherwise keep reading from the vao's buffers
+	if vao.created {
+		bindVertexArray(vao.ID)
+
+		for _, attrib := range old.attributes {
+			gl.DisableVertexAttribArray(attrib)
+		}
+
+		bindVertexArray(0)
+	}
+
+	vao.AttachProgram(program)
+	vao.defaultShader = false
+
+	if vao.created {
+		vao.bindAttributes()
+	}
+}
+
 /*
 Shader uniform implementation
 */
@@ -318,12 +649,38 @@ func (vao *VAO) AddUniform(name string, value interface{}) {
 	vao.uniforms[name] = value
 }
 
+func (vao *VAO) SetUniform(name string, value interface{}) {
+	vao.shader.SetUniform(name, value)
+
+	vao.uniforms[name] = value
+}
+
 func (vao *VAO) PrepUniforms() {
 	for id, uni := range vao.shader.uniforms {
 		vao.shader.SetUniform(id, uni.Value())
 	}
 }
 
+// rotateAbout ... rotate pos about rot.xy where rot.zw are the cosine and sine of the rotation
+func rotateAbout(pos mgl32.Vec2, rot mgl32.Vec4) mgl32.Vec2 {
+	x, y := pos.X()-rot.X(), pos.Y()-rot.Y()
+
+	return mgl32.Vec2{
+		rot.Z()*x - rot.W()*y + rot.X(),
+		rot.W()*x + rot.Z()*y + rot.Y(),
+	}
+}
+
+func whiteColours(size uint32) []float32 {
+	colours := make([]float32, size*DEFAULT_COLOUR_SIZE)
+
+	for i := range colours {
+		colours[i] = 1
+	}
+
+	return colours
+}
+
 /*
 converts an array of Vec3's into a float32 array for use by vbo's
 */
@@ -0,0 +1,66 @@
+package graphics
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Stencil outlines, the object is drawn marking the stencil buffer then drawn again scaled up in a solid colour
+everywhere it is not marked. Uses the highest stencil bit so can be used inside clips.
+*/
+
+const outlineBit = 0x80
+
+// DrawWithOutline ... render the object with an outline thickness pixels wide, use in place of obj.Render.
+// Outlines need the default shader's scale and fill, render objects using custom shaders are rendered without one.
+func (obj *RenderObject) DrawWithOutline(color Color, thickness float32) {
+	x, y, width, height := obj.Bounds()
+
+	if width == 0 || height == 0 || !obj.vao.UsesDefaultShader() {
+		obj.Render()
+
+		return
+	}
+
+	if clipDepth == 0 {
+		gl.Enable(gl.STENCIL_TEST)
+	}
+
+	gl.StencilMask(outlineBit)
+
+	// Draw the object marking where it has been drawn, only inside any current clip
+	gl.StencilFunc(gl.EQUAL, clipDepth|outlineBit, ^uint32(outlineBit)&0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.REPLACE)
+	obj.Render()
+
+	// Draw the scaled up silhouette outside the mark
+	gl.StencilFunc(gl.EQUAL, clipDepth, 0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+
+	cX, cY := x+width/2, y+height/2
+	obj.vao.SetScale(cX, cY, (width+2*thickness)/width, (height+2*thickness)/height)
+	obj.vao.SetFill(color.vec4(), true)
+	obj.Render()
+
+	obj.vao.SetScale(0, 0, 1, 1)
+	obj.vao.SetFill(mgl32.Vec4{}, false)
+
+	// Remove the mark so later outlines are unaffected
+	gl.ColorMask(false, false, false, false)
+	gl.StencilFunc(gl.ALWAYS, 0, 0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.ZERO)
+	obj.Render()
+	gl.ColorMask(true, true, true, true)
+
+	gl.StencilMask(0xFF)
+	gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+
+	if clipDepth == 0 {
+		gl.Disable(gl.STENCIL_TEST)
+
+		return
+	}
+
+	gl.StencilFunc(gl.EQUAL, clipDepth, 0xFF)
+}
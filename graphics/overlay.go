@@ -0,0 +1,42 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Fullscreen overlays, queued colours are drawn over everything at the very end of the next Render then discarded.
+Must be called on the main thread, call every frame while the overlay should be visible.
+*/
+
+var overlays []Color
+
+// DrawFullscreenColor ... cover the whole window in c for the next frame, alpha blended so the alpha can drive fades
+func DrawFullscreenColor(c Color) {
+	overlays = append(overlays, c)
+}
+
+func renderOverlays() {
+	if len(overlays) == 0 {
+		return
+	}
+
+	quad := []float32{
+		0, 0,
+		windowWidth, 0,
+		windowWidth, windowHeight,
+		0, windowHeight,
+	}
+
+	applyBlendMode(BlendAlpha)
+
+	for _, c := range overlays {
+		opengl.DrawImmediate(gl.TRIANGLE_FAN, quad, c.vec4(), windowWidth, windowHeight)
+	}
+
+	applyBlendMode(globalBlendMode)
+
+	overlays = overlays[:0]
+}
@@ -0,0 +1,111 @@
+package graphics
+
+import (
+	"fmt"
+)
+
+/*
+Render passes, each frame Render executes every enabled pass in registration order.
+The default pass renders all render objects and is registered first.
+*/
+
+const DefaultPass = "default"
+
+type renderPass struct {
+	name    string
+	fn      func()
+	enabled bool
+}
+
+var renderPasses = []*renderPass{
+	{DefaultPass, renderDefaultPass, true},
+}
+
+// AddPass ... register a named pass, fn is called on the main thread each frame after all previously added passes
+func AddPass(name string, fn func()) {
+	if findPass(name) != nil {
+		panic(fmt.Errorf("render pass %s already exists", name))
+	}
+
+	renderPasses = append(renderPasses, &renderPass{name, fn, true})
+}
+
+func SetPassEnabled(name string, enabled bool) {
+	pass := findPass(name)
+
+	if pass == nil {
+		panic(fmt.Errorf("render pass %s does not exist", name))
+	}
+
+	pass.enabled = enabled
+}
+
+func findPass(name string) *renderPass {
+	for _, pass := range renderPasses {
+		if pass.name == name {
+			return pass
+		}
+	}
+
+	return nil
+}
+
+/*
+Render hooks, called on the main thread immediately before and after all render passes in registration order
+*/
+
+var (
+	preRenderHooks  []func()
+	postRenderHooks []func()
+)
+
+func OnPreRender(fn func()) {
+	preRenderHooks = append(preRenderHooks, fn)
+}
+
+func OnPostRender(fn func()) {
+	postRenderHooks = append(postRenderHooks, fn)
+}
+
+func renderPassList() {
+	for _, hook := range preRenderHooks {
+		hook()
+	}
+
+	for _, pass := range renderPasses {
+		if pass.enabled {
+			pass.fn()
+		}
+	}
+
+	for _, hook := range postRenderHooks {
+		hook()
+	}
+}
+
+var reverseDrawOrder = false
+
+// SetReverseDrawOrder ... draw render objects in the default pass newest first, so newer objects are drawn underneath
+func SetReverseDrawOrder(reverse bool) {
+	reverseDrawOrder = reverse
+}
+
+func renderDefaultPass() {
+	if transparencySort {
+		renderSortedPass()
+
+		return
+	}
+
+	if reverseDrawOrder {
+		for i := len(renderObjects) - 1; i >= 0; i-- {
+			renderObjects[i].Render()
+		}
+
+		return
+	}
+
+	for _, obj := range renderObjects {
+		obj.Render()
+	}
+}
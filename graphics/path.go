@@ -0,0 +1,154 @@
+package graphics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Paths, Catmull-Rom splines passing through every control point for moving sprites, platforms or cameras along curves.
+Points along a path can be found either by a parameter spread evenly over the segments, or by distance along the
+path for constant speed movement.
+*/
+
+// Arc length samples per segment, more give more accurate constant speed movement
+const pathSamples = 16
+
+type Path struct {
+	points  []mgl32.Vec2
+	closed  bool
+	lengths []float32 // Cumulative length at each sample, pathSamples per segment
+}
+
+// CreatePath ... a path through points in pixels, at least two are needed. A closed path loops from the last point back to the first.
+func CreatePath(points []mgl32.Vec2, closed bool) (*Path, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("a path needs at least 2 points, %d given", len(points))
+	}
+
+	p := &Path{
+		append([]mgl32.Vec2{}, points...),
+		closed,
+		nil,
+	}
+
+	p.measure()
+
+	return p, nil
+}
+
+func (p *Path) segments() int {
+	if p.closed {
+		return len(p.points)
+	}
+
+	return len(p.points) - 1
+}
+
+// point ... control point i, wrapping for closed paths and clamping to the ends for open paths
+func (p *Path) point(i int) mgl32.Vec2 {
+	n := len(p.points)
+
+	if p.closed {
+		return p.points[((i%n)+n)%n]
+	}
+
+	if i < 0 {
+		return p.points[0]
+	}
+
+	if i >= n {
+		return p.points[n-1]
+	}
+
+	return p.points[i]
+}
+
+// PointAt ... the point a fraction t along the path, each segment covers an equal range of t regardless of its length.
+// t is clamped to 0 to 1 for open paths and wraps for closed paths.
+func (p *Path) PointAt(t float32) mgl32.Vec2 {
+	if p.closed {
+		t -= float32(math.Floor(float64(t)))
+	} else {
+		t = mgl32.Clamp(t, 0, 1)
+	}
+
+	scaled := t * float32(p.segments())
+	segment := int(scaled)
+
+	if segment >= p.segments() {
+		segment = p.segments() - 1
+	}
+
+	return p.segmentPoint(segment, scaled-float32(segment))
+}
+
+// segmentPoint ... Catmull-Rom interpolation between control points segment and segment+1
+func (p *Path) segmentPoint(segment int, u float32) mgl32.Vec2 {
+	p0 := p.point(segment - 1)
+	p1 := p.point(segment)
+	p2 := p.point(segment + 1)
+	p3 := p.point(segment + 2)
+
+	u2 := u * u
+	u3 := u2 * u
+
+	return p1.Mul(2).
+		Add(p2.Sub(p0).Mul(u)).
+		Add(p0.Mul(2).Sub(p1.Mul(5)).Add(p2.Mul(4)).Sub(p3).Mul(u2)).
+		Add(p1.Mul(3).Sub(p0).Sub(p2.Mul(3)).Add(p3).Mul(u3)).
+		Mul(0.5)
+}
+
+func (p *Path) measure() {
+	samples := p.segments() * pathSamples
+	p.lengths = make([]float32, samples+1)
+
+	previous := p.PointAt(0)
+
+	for i := 1; i <= samples; i++ {
+		segment := (i - 1) / pathSamples
+		point := p.segmentPoint(segment, float32(i-segment*pathSamples)/pathSamples)
+
+		p.lengths[i] = p.lengths[i-1] + point.Sub(previous).Len()
+		previous = point
+	}
+}
+
+// Length ... approximate length of the path in pixels
+func (p *Path) Length() float32 {
+	return p.lengths[len(p.lengths)-1]
+}
+
+// PointAtDistance ... the point distance pixels along the path, increasing distance at a constant rate moves at a
+// constant speed. distance is clamped to the path for open paths and wraps for closed paths.
+func (p *Path) PointAtDistance(distance float32) mgl32.Vec2 {
+	length := p.Length()
+
+	if length == 0 {
+		return p.points[0]
+	}
+
+	if p.closed {
+		distance -= length * float32(math.Floor(float64(distance/length)))
+	} else {
+		distance = mgl32.Clamp(distance, 0, length)
+	}
+
+	// First sample at or beyond the distance
+	i := sort.Search(len(p.lengths), func(i int) bool {
+		return p.lengths[i] >= distance
+	})
+
+	if i == 0 {
+		return p.PointAt(0)
+	}
+
+	between := (distance - p.lengths[i-1]) / (p.lengths[i] - p.lengths[i-1])
+	t := (float32(i-1) + between) / float32(len(p.lengths)-1)
+
+	return p.PointAt(t)
+}
@@ -0,0 +1,42 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// PrewarmShaders ... compile and link all built in shaders at load time instead of on first use, returning how long it
+// took. Programs registered by opengl.ShaderWithVertex and opengl.ShaderWithFragment are already linked, with dummyDraw
+// they and each built in shader are also used for a draw that covers no pixels, forcing the driver to finish compiling.
+func PrewarmShaders(dummyDraw bool) time.Duration {
+	start := time.Now()
+
+	opengl.PrecompileShader(opengl.DEFAULT_VERT_SHADER, opengl.VERTSHADER)
+	opengl.PrecompileShader(opengl.DEFAULT_FRAG_SHADER, opengl.FRAGSHADER)
+	opengl.PrewarmImmediate()
+
+	if dummyDraw {
+		// Degenerate triangle, every vertex at the origin
+		opengl.DrawImmediate(gl.TRIANGLES, make([]float32, 6), mgl32.Vec4{}, windowWidth, windowHeight)
+
+		opengl.TextureFromImage(debugFontTexture, debugFontImage())
+
+		obj := &RenderObject{}
+		createRenderObject(obj, 3, debugFontTexture, true)
+		obj.Render()
+
+		for _, program := range opengl.LinkedPrograms() {
+			obj.SetShader(program)
+			obj.Render()
+		}
+
+		obj.Delete()
+	}
+
+	gl.Finish()
+
+	return time.Since(start)
+}
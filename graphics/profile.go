@@ -0,0 +1,146 @@
+package graphics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"gopengl/graphics/opengl"
+	"os"
+	"strconv"
+	"sync"
+)
+
+/*
+Frame statistics, counted for every render object drawn by Render and optionally logged to a csv file each frame.
+Immediate drawing (debug lines, clips, overlays) is not counted.
+*/
+
+type FrameStats struct {
+	FrameTime float32 // Seconds since the previous frame
+	DrawCalls int
+	Vertices  int
+}
+
+var (
+	frameStats   FrameStats // Stats of the last complete frame
+	currentStats FrameStats // Stats of the frame being rendered
+)
+
+// Stats ... statistics of the last rendered frame
+func Stats() FrameStats {
+	return frameStats
+}
+
+func countDraw(vertices int32) {
+	currentStats.DrawCalls++
+	currentStats.Vertices += int(vertices)
+}
+
+func endFrameStats() {
+	currentStats.FrameTime = deltaTime
+	frameStats = currentStats
+	currentStats = FrameStats{}
+	redundantStateChanges = opengl.ResetStateTracking()
+
+	if profileLog != nil {
+		profileLog.log(frameStats)
+	}
+}
+
+/*
+Redundant state changes, a debug aid for checking batching is effective. Tracking is disabled by default as it adds a
+check to every bind.
+*/
+
+var redundantStateChanges = 0
+
+// SetStateChangeTracking ... enable counting redundant program, vertex array, buffer and texture binds each frame
+func SetStateChangeTracking(enabled bool) {
+	opengl.SetStateTracking(enabled)
+}
+
+// RedundantStateChanges ... rebinds of the last bound program, vertex array, buffer or texture during the last frame,
+// always 0 unless tracking is enabled
+func RedundantStateChanges() int {
+	return redundantStateChanges
+}
+
+/*
+Profile logging, frames are written on a separate go routine so file writes never stall rendering.
+If the writer falls behind frames are dropped rather than blocking.
+*/
+
+type profileLogger struct {
+	file   *os.File
+	frames chan FrameStats
+	done   sync.WaitGroup
+}
+
+var profileLog *profileLogger
+
+const profileLogBuffer = 256
+
+// StartProfileLog ... append the stats of every frame to the csv file at path until StopProfileLog is called, the
+// file is created or truncated. GPU time is not recorded.
+func StartProfileLog(path string) error {
+	if profileLog != nil {
+		return fmt.Errorf("profile log already running")
+	}
+
+	file, err := os.Create(path)
+
+	if err != nil {
+		return fmt.Errorf("cannot create profile log %s: %v", path, err)
+	}
+
+	profileLog = &profileLogger{
+		file:   file,
+		frames: make(chan FrameStats, profileLogBuffer),
+	}
+
+	profileLog.done.Add(1)
+
+	go profileLog.write()
+
+	return nil
+}
+
+// StopProfileLog ... stop logging, blocking until every logged frame has been written
+func StopProfileLog() {
+	if profileLog == nil {
+		return
+	}
+
+	close(profileLog.frames)
+	profileLog.done.Wait()
+	profileLog.file.Close()
+	profileLog = nil
+}
+
+func (l *profileLogger) log(stats FrameStats) {
+	select {
+	case l.frames <- stats:
+	default:
+	}
+}
+
+func (l *profileLogger) write() {
+	defer l.done.Done()
+
+	writer := csv.NewWriter(l.file)
+	writer.Write([]string{"frame", "frame_time_ms", "draw_calls", "vertices"})
+
+	frame := 0
+
+	for stats := range l.frames {
+		writer.Write([]string{
+			strconv.Itoa(frame),
+			strconv.FormatFloat(float64(stats.FrameTime)*1000, 'f', 3, 32),
+			strconv.Itoa(stats.DrawCalls),
+			strconv.Itoa(stats.Vertices),
+		})
+
+		frame++
+	}
+
+	writer.Flush()
+}
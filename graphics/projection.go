@@ -0,0 +1,185 @@
+package graphics
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Projection, an orthographic projection from pixel coordinates with the origin at the top left of the window by default.
+All render objects use the same projection, it is uploaded to each before rendering.
+*/
+
+var (
+	depthNear  float32 = -1
+	depthFar   float32 = 1
+	flipX      float32 = 1
+	flipY      float32 = 1
+	projection         = mgl32.Ortho(0, windowWidth, windowHeight, 0, depthNear, depthFar)
+)
+
+var customProjection = false
+
+// Always from top left pixel coordinates, used by internal render objects
+var screenProjection = projection
+
+/*
+Coordinate origins, where 0, 0 is and which way y increases for all pixel coordinates given to the package
+*/
+
+type Origin int
+
+const (
+	OriginTopLeft    Origin = iota // y increases downwards, the default
+	OriginBottomLeft Origin = iota // y increases upwards
+	OriginCenter     Origin = iota // 0, 0 is the centre of the window, y increases upwards
+)
+
+var coordinateOrigin = OriginTopLeft
+
+// SetCoordinateOrigin ... set the origin of every pixel coordinate, vertices, translations, debug drawing, clips and
+// PixelAt all use it. Textures are unaffected, texture coordinates always start at the top left of the texture.
+func SetCoordinateOrigin(origin Origin) {
+	coordinateOrigin = origin
+
+	updateProjection()
+	updateFrontFace()
+}
+
+// toTopLeft ... convert a point from the coordinate origin to top left pixel coordinates
+func toTopLeft(x, y float32) (float32, float32) {
+	switch coordinateOrigin {
+	case OriginBottomLeft:
+		return x, windowHeight - y
+	case OriginCenter:
+		return x + windowWidth/2, windowHeight/2 - y
+	default:
+		return x, y
+	}
+}
+
+// pointsToTopLeft ... convert x, y pairs from the coordinate origin to top left pixel coordinates
+func pointsToTopLeft(points []float32) []float32 {
+	converted := make([]float32, len(points))
+
+	for i := 0; i+1 < len(points); i += 2 {
+		converted[i], converted[i+1] = toTopLeft(points[i], points[i+1])
+	}
+
+	return converted
+}
+
+// yUp ... whether y increases upwards for the render object
+func (obj *RenderObject) yUp() bool {
+	return !obj.screen && coordinateOrigin != OriginTopLeft
+}
+
+// flipTexY ... with y up the first vertex of a rect is its bottom, so texture coordinates are flipped to keep textures upright
+func (obj *RenderObject) flipTexY(yTex, heightTex float32) (float32, float32) {
+	if obj.yUp() {
+		return yTex + heightTex, -heightTex
+	}
+
+	return yTex, heightTex
+}
+
+// originMatrix ... transform from the coordinate origin to top left pixel coordinates
+func originMatrix() mgl32.Mat4 {
+	switch coordinateOrigin {
+	case OriginBottomLeft:
+		return mgl32.Translate3D(0, windowHeight, 0).Mul4(mgl32.Scale3D(1, -1, 1))
+	case OriginCenter:
+		return mgl32.Translate3D(windowWidth/2, windowHeight/2, 0).Mul4(mgl32.Scale3D(1, -1, 1))
+	default:
+		return mgl32.Ident4()
+	}
+}
+
+// SetProjection ... replace the default orthographic projection, for isometric, perspective or skewed views.
+// While set translations are in the same coordinates as vertices rather than being normalized to the window,
+// flips, depth ranges and window resizes no longer change the projection.
+func SetProjection(m mgl32.Mat4) {
+	projection = m
+	customProjection = true
+}
+
+// ResetProjection ... return to the default orthographic projection
+func ResetProjection() {
+	customProjection = false
+	updateProjection()
+}
+
+// SetDepthRange ... set the range of depths visible, render objects with a depth outside of the range are clipped.
+// The window depth range used by gl.DepthRange is left as 0 to 1 so the full depth buffer precision covers near to far.
+func SetDepthRange(near, far float32) error {
+	if near >= far {
+		return fmt.Errorf("depth range near %v must be less than far %v", near, far)
+	}
+
+	depthNear = near
+	depthFar = far
+	updateProjection()
+
+	return nil
+}
+
+var depthTest = false
+
+// SetDepthTest ... enable depth testing so render objects with a lower depth are drawn in front regardless of draw order
+func SetDepthTest(enabled bool) {
+	depthTest = enabled
+
+	if enabled {
+		gl.Enable(gl.DEPTH_TEST)
+		gl.DepthFunc(gl.LEQUAL)
+		gl.DepthRange(0, 1)
+
+		return
+	}
+
+	gl.Disable(gl.DEPTH_TEST)
+}
+
+// SetGlobalFlip ... mirror the entire scene horizontally and/or vertically. Mirroring in a single axis reverses
+// triangle winding so the front face is swapped to keep culling consistent.
+func SetGlobalFlip(horizontal, vertical bool) {
+	flipX, flipY = 1, 1
+
+	if horizontal {
+		flipX = -1
+	}
+
+	if vertical {
+		flipY = -1
+	}
+
+	updateProjection()
+	updateFrontFace()
+}
+
+// updateFrontFace ... every mirroring, by flips or a y up origin, reverses triangle winding
+func updateFrontFace() {
+	mirrored := (flipX < 0) != (flipY < 0)
+
+	if coordinateOrigin != OriginTopLeft {
+		mirrored = !mirrored
+	}
+
+	if mirrored {
+		gl.FrontFace(gl.CW)
+	} else {
+		gl.FrontFace(gl.CCW)
+	}
+}
+
+func updateProjection() {
+	screenProjection = mgl32.Scale3D(flipX, flipY, 1).Mul4(mgl32.Ortho(0, windowWidth, windowHeight, 0, depthNear, depthFar))
+
+	if customProjection {
+		return
+	}
+
+	projection = screenProjection.Mul4(originMatrix())
+}
@@ -0,0 +1,126 @@
+package graphics
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var origins = []struct {
+	name   string
+	origin Origin
+}{
+	{"top left", OriginTopLeft},
+	{"bottom left", OriginBottomLeft},
+	{"centre", OriginCenter},
+}
+
+// withOrigin ... run fn with origin on an 800x600 window, set directly as SetCoordinateOrigin needs a context
+func withOrigin(origin Origin, fn func()) {
+	savedOrigin := coordinateOrigin
+	savedWidth, savedHeight := windowWidth, windowHeight
+
+	coordinateOrigin = origin
+	windowWidth, windowHeight = 800, 600
+
+	fn()
+
+	coordinateOrigin = savedOrigin
+	windowWidth, windowHeight = savedWidth, savedHeight
+}
+
+func TestToTopLeft(t *testing.T) {
+	tests := []struct {
+		origin Origin
+		x, y   float32
+		wX, wY float32
+	}{
+		{OriginTopLeft, 10, 20, 10, 20},
+		{OriginBottomLeft, 10, 20, 10, 580},
+		{OriginBottomLeft, 0, 0, 0, 600},
+		{OriginCenter, 0, 0, 400, 300},
+		{OriginCenter, -400, 300, 0, 0},
+	}
+
+	for _, test := range tests {
+		withOrigin(test.origin, func() {
+			x, y := toTopLeft(test.x, test.y)
+
+			if x != test.wX || y != test.wY {
+				t.Errorf("origin %d: toTopLeft(%v, %v) = %v, %v, want %v, %v", test.origin, test.x, test.y, x, y, test.wX, test.wY)
+			}
+		})
+	}
+}
+
+func TestPointsToTopLeft(t *testing.T) {
+	points := []float32{0, 0, 100, 50}
+	want := map[Origin][]float32{
+		OriginTopLeft:    {0, 0, 100, 50},
+		OriginBottomLeft: {0, 600, 100, 550},
+		OriginCenter:     {400, 300, 500, 250},
+	}
+
+	for _, o := range origins {
+		withOrigin(o.origin, func() {
+			converted := pointsToTopLeft(points)
+
+			for i := range converted {
+				if converted[i] != want[o.origin][i] {
+					t.Errorf("%s: pointsToTopLeft(%v) = %v, want %v", o.name, points, converted, want[o.origin])
+
+					break
+				}
+			}
+		})
+	}
+
+	// The input must not be modified
+	if points[1] != 0 || points[3] != 50 {
+		t.Errorf("pointsToTopLeft modified its input, got %v", points)
+	}
+}
+
+func TestFlipTexY(t *testing.T) {
+	tests := []struct {
+		origin      Origin
+		screen      bool
+		wY, wHeight float32
+	}{
+		{OriginTopLeft, false, 16, 8},
+		{OriginBottomLeft, false, 24, -8},
+		{OriginCenter, false, 24, -8},
+		// Internal screen objects are always top left
+		{OriginBottomLeft, true, 16, 8},
+		{OriginCenter, true, 16, 8},
+	}
+
+	for _, test := range tests {
+		withOrigin(test.origin, func() {
+			obj := &RenderObject{screen: test.screen}
+			y, height := obj.flipTexY(16, 8)
+
+			if y != test.wY || height != test.wHeight {
+				t.Errorf("origin %d, screen %v: flipTexY(16, 8) = %v, %v, want %v, %v", test.origin, test.screen, y, height, test.wY, test.wHeight)
+			}
+		})
+	}
+}
+
+func TestOriginMatrix(t *testing.T) {
+	for _, o := range origins {
+		withOrigin(o.origin, func() {
+			m := originMatrix()
+
+			// Every point must land where toTopLeft puts it
+			for _, p := range []mgl32.Vec2{{0, 0}, {800, 600}, {-120, 45}} {
+				got := m.Mul4x1(mgl32.Vec4{p.X(), p.Y(), 0, 1})
+				wX, wY := toTopLeft(p.X(), p.Y())
+
+				if !mgl32.FloatEqual(got.X(), wX) || !mgl32.FloatEqual(got.Y(), wY) {
+					t.Errorf("%s: originMatrix maps %v to %v, %v, want %v, %v", o.name, p, got.X(), got.Y(), wX, wY)
+				}
+			}
+		})
+	}
+}
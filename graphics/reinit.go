@@ -0,0 +1,117 @@
+package graphics
+
+import (
+	"fmt"
+	"gopengl/graphics/opengl"
+	"sync/atomic"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+/*
+Reinitialization, settings which are fixed when the context is created are changed by recreating the window and
+context. Everything is rebuilt from cpu side data so render objects and textures remain valid.
+*/
+
+// Options ... settings fixed when the window and its context are created
+type Options struct {
+	Width, Height int
+	Title         string
+	Samples       int  // Multisample anti-aliasing samples per pixel, 0 disables msaa
+	SRGB          bool // Convert linear colours to srgb when writing to the framebuffer
+	Resizable     bool // Allow the user to resize the window, windows are a fixed size by default
+}
+
+// Reinitialize ... recreate the window and context with opts, rebuilding every render object and texture.
+// Must be called on the main thread. The old window is kept if the new window or its opengl functions cannot be
+// created. Textures are restored as 8 bit rgba without swizzles, shader programs are relinked from their sources and
+// programs wrapped with opengl.CreateProgram are replaced by the default shader. If a program fails to relink the
+// rest of the context is still rebuilt, render objects using it fall back to the default shader and the error is returned.
+func Reinitialize(opts Options) error {
+	if window == nil {
+		return fmt.Errorf("cannot reinitialize before a window is created")
+	}
+
+	newWindow, err := createWindow(opts)
+
+	if err != nil {
+		window.MakeContextCurrent()
+
+		return fmt.Errorf("cannot recreate window: %v", err)
+	}
+
+	if err := gl.Init(); err != nil {
+		newWindow.Destroy()
+		window.MakeContextCurrent()
+
+		return fmt.Errorf("cannot initialize opengl: %v", err)
+	}
+
+	// Everything to retain or free belongs to the old context
+	window.MakeContextCurrent()
+	releaseContext()
+	window.Destroy()
+
+	newWindow.MakeContextCurrent()
+	SetWindow(newWindow)
+
+	err = recoverContext()
+	SetWindowSize(float32(opts.Width), float32(opts.Height))
+
+	if opts.SRGB {
+		gl.Enable(gl.FRAMEBUFFER_SRGB)
+	}
+
+	return err
+}
+
+// releaseContext ... retain everything needed to rebuild in a new context and free what is rebuilt on demand
+func releaseContext() {
+	opengl.SnapshotTextures()
+
+	deleteDebug()
+	deleteSupersampling()
+	deleteBackground()
+	opengl.DeleteImmediate()
+	opengl.ResetShaders()
+}
+
+// recoverContext ... rebuild everything in the current context after releaseContext
+func recoverContext() error {
+	// Capabilities may differ in the new context
+	glMajor, glMinor = 0, 0
+	glExtensions = nil
+
+	opengl.RestoreTextures()
+	opengl.RestoreSamplers()
+
+	// Failures are returned once everything is rebuilt so the context is always left usable
+	var err error
+
+	if relinkErr := opengl.RelinkPrograms(); relinkErr != nil {
+		err = fmt.Errorf("cannot relink shader programs: %v", relinkErr)
+	}
+
+	for _, obj := range renderObjects {
+		if recreateErr := obj.vao.Recreate(); recreateErr != nil && err == nil {
+			err = recreateErr
+		}
+	}
+
+	if backgroundTexture != nil {
+		SetBackgroundTexture(backgroundTexture)
+	}
+
+	applySizeLimits()
+	applyAspectRatio()
+	applyBlendMode(globalBlendMode)
+	updateFrontFace()
+	SetDepthTest(depthTest)
+
+	// Force the swap interval to be reapplied to the new context
+	appliedSwapInterval = atomic.LoadInt32(&swapInterval)
+	glfw.SwapInterval(int(appliedSwapInterval))
+
+	return err
+}
@@ -0,0 +1,30 @@
+package graphics
+
+import (
+	"fmt"
+	"gopengl/graphics/opengl"
+)
+
+/*
+Samplers, one set of filtering and wrapping parameters shared by many textures without setting them per texture
+*/
+
+// CreateSampler ... returns an error if the context does not support sampler objects, they require opengl 3.3 or
+// GL_ARB_sampler_objects. Must be called on the main thread.
+func CreateSampler() (*opengl.Sampler, error) {
+	major, minor := SupportedGLVersion()
+
+	if major < 3 || (major == 3 && minor < 3) {
+		if !HasExtension("GL_ARB_sampler_objects") {
+			return nil, fmt.Errorf("sampler objects are not supported by opengl %d.%d", major, minor)
+		}
+	}
+
+	return opengl.CreateSampler(), nil
+}
+
+// SetSampler ... sample the texture bound to unit using s while the render object is rendered, eg
+// obj.SetSampler(obj.Vao().Texture.Unit(), s). Pass nil to use the texture's own parameters again.
+func (obj *RenderObject) SetSampler(unit uint32, s *opengl.Sampler) {
+	obj.vao.SetSampler(unit, s)
+}
@@ -0,0 +1,54 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+const solidRedFrag = `#version 410
+in vec2 fragtexcoord;
+in vec4 fragcolour;
+out vec4 frag_colour;
+void main(){
+    frag_colour=vec4(1.,0.,0.,1.);
+}`
+
+func TestSetShaderRenders(t *testing.T) {
+	withTestContext(t, func() {
+		obj := createSpriteObject(1)
+		defer deleteSpriteObject(obj)
+
+		program, err := opengl.ShaderWithFragment(solidRedFrag)
+
+		if err != nil {
+			t.Fatalf("cannot create shader: %v", err)
+		}
+
+		// Every uniform set while rendering must exist on the new program
+		obj.SetShader(program)
+
+		gl.ClearColor(0, 0, 0, 1)
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+
+		for gl.GetError() != gl.NO_ERROR {
+		}
+
+		obj.Render()
+
+		if errCode := gl.GetError(); errCode != gl.NO_ERROR {
+			t.Fatalf("rendering with a custom shader raised gl error 0x%x", errCode)
+		}
+
+		colour, err := PixelAt(8, 8)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if red := (Color{1, 0, 0, 1}); colour != red {
+			t.Errorf("pixel inside the square is %v, want %v", colour, red)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+	"sort"
+)
+
+/*
+Sprite sheet slicing, regions are in texture pixels ready to be passed to the texture methods of render objects
+*/
+
+// SpriteSheet ... a texture split into frames which can be applied to squares of render objects
+type SpriteSheet struct {
+	texture *opengl.Texture
+	frames  []Rect
+	inset   float32
+}
+
+// CreateSpriteSheet ... split tex into a uniform grid of cols by rows frames
+func CreateSpriteSheet(tex *opengl.Texture, cols, rows int) *SpriteSheet {
+	return &SpriteSheet{
+		tex,
+		SliceSpriteSheet(tex, cols, rows),
+		0,
+	}
+}
+
+// SetInset ... shrink every frame by texels on each side, preventing neighbouring frames bleeding in when sampling at
+// frame edges. Around half a texel usually suffices with nearest filtering, more may be needed with linear filtering.
+func (s *SpriteSheet) SetInset(texels float32) {
+	s.inset = texels
+}
+
+// Frame ... region of the frame in texture pixels with the inset applied
+func (s *SpriteSheet) Frame(i int) Rect {
+	frame := s.frames[i]
+
+	return Rect{
+		frame.X + s.inset,
+		frame.Y + s.inset,
+		frame.Width - 2*s.inset,
+		frame.Height - 2*s.inset,
+	}
+}
+
+func (s *SpriteSheet) FrameCount() int {
+	return len(s.frames)
+}
+
+// ApplyFrame ... set the texture coordinates of the square at index to a frame
+func (s *SpriteSheet) ApplyFrame(obj *RenderObject, index, frame int) {
+	f := s.Frame(frame)
+
+	obj.ModifyTexRect(index, f.X, f.Y, f.Width, f.Height)
+}
+
+// SliceSpriteSheet ... split a texture into a uniform grid of cols by rows regions in row-major order
+func SliceSpriteSheet(tex *opengl.Texture, cols, rows int) []Rect {
+	width := float32(tex.Width()) / float32(cols)
+	height := float32(tex.Height()) / float32(rows)
+	regions := make([]Rect, 0, cols*rows)
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			regions = append(regions, Rect{float32(col) * width, float32(row) * height, width, height})
+		}
+	}
+
+	return regions
+}
+
+// SliceSpriteSheetRegions ... find the bounding box of every connected non-transparent region of a texture for
+// irregular sheets. Regions are returned in row-major order, overlapping rows are grouped together.
+func SliceSpriteSheetRegions(tex *opengl.Texture) []Rect {
+	width, height := tex.Width(), tex.Height()
+	pixels := tex.Pixels()
+	visited := make([]bool, width*height)
+	regions := make([]Rect, 0)
+
+	opaque := func(i int) bool {
+		return pixels[i*4+3] > 0
+	}
+
+	for start := range visited {
+		if visited[start] || !opaque(start) {
+			continue
+		}
+
+		// Flood fill the region tracking its bounds
+		minX, minY, maxX, maxY := start%width, start/width, start%width, start/width
+		stack := []int{start}
+		visited[start] = true
+
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%width, i/width
+
+			if x < minX {
+				minX = x
+			}
+
+			if x > maxX {
+				maxX = x
+			}
+
+			if y < minY {
+				minY = y
+			}
+
+			if y > maxY {
+				maxY = y
+			}
+
+			neighbours := []int{}
+
+			if x > 0 {
+				neighbours = append(neighbours, i-1)
+			}
+
+			if x < width-1 {
+				neighbours = append(neighbours, i+1)
+			}
+
+			if y > 0 {
+				neighbours = append(neighbours, i-width)
+			}
+
+			if y < height-1 {
+				neighbours = append(neighbours, i+width)
+			}
+
+			for _, n := range neighbours {
+				if !visited[n] && opaque(n) {
+					visited[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+
+		regions = append(regions, Rect{float32(minX), float32(minY), float32(maxX - minX + 1), float32(maxY - minY + 1)})
+	}
+
+	sortRowMajor(regions)
+
+	return regions
+}
+
+// sortRowMajor ... sort regions top to bottom, regions overlapping vertically form a row sorted left to right
+func sortRowMajor(regions []Rect) {
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].Y < regions[j].Y
+	})
+
+	rowStart := 0
+	rowBottom := float32(0)
+
+	for i, region := range regions {
+		if i > 0 && region.Y >= rowBottom {
+			sortRow(regions[rowStart:i])
+			rowStart = i
+		}
+
+		if i == rowStart || region.Y+region.Height > rowBottom {
+			rowBottom = region.Y + region.Height
+		}
+	}
+
+	sortRow(regions[rowStart:])
+}
+
+func sortRow(row []Rect) {
+	sort.Slice(row, func(i, j int) bool {
+		return row[i].X < row[j].X
+	})
+}
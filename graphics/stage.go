@@ -0,0 +1,169 @@
+package graphics
+
+import (
+	"fmt"
+	"gopengl/graphics/opengl"
+	"sync"
+)
+
+/*
+Vertex staging, geometry can be built into a stage on any go routine and then committed for a single upload on the
+main thread. Every render object has two stages, while one is being uploaded the other can be filled.
+A committed stage replaces all of the render object's squares.
+*/
+
+type VertexStage struct {
+	obj     *RenderObject
+	verts   []float32
+	texs    []float32 // In pixels, normalized on upload as the texture may change before then
+	colours []float32
+	colour  Color // Colour of subsequently staged vertices
+}
+
+type vertexStaging struct {
+	stageLock  sync.Mutex // Guards swapping the stages
+	commitLock sync.Mutex // Only one stage can be uploading at a time
+	stages     [2]*VertexStage
+	back       int // Index of the stage currently returned by Stage
+}
+
+func createVertexStaging(obj *RenderObject) *vertexStaging {
+	return &vertexStaging{
+		stages: [2]*VertexStage{
+			{obj, make([]float32, 0), make([]float32, 0), make([]float32, 0), White},
+			{obj, make([]float32, 0), make([]float32, 0), make([]float32, 0), White},
+		},
+	}
+}
+
+// Stage ... the stage to build the render object's next geometry in, safe to call from any go routine.
+// Only one go routine should write to a stage at a time.
+func (obj *RenderObject) Stage() *VertexStage {
+	obj.staging.stageLock.Lock()
+	defer obj.staging.stageLock.Unlock()
+
+	return obj.staging.stages[obj.staging.back]
+}
+
+// swap ... make s the front stage, the next call to Stage returns the other, emptied, stage
+func (staging *vertexStaging) swap(s *VertexStage) {
+	staging.stageLock.Lock()
+	defer staging.stageLock.Unlock()
+
+	if s != staging.stages[staging.back] {
+		panic(fmt.Errorf("cannot commit a stage which is not the render object's current stage"))
+	}
+
+	staging.back ^= 1
+	staging.stages[staging.back].Reset()
+}
+
+// Commit ... upload s, replacing all of the render object's squares. Must be called on the main thread, use CommitJob elsewhere
+func (obj *RenderObject) Commit(s *VertexStage) {
+	obj.staging.swap(s)
+	obj.upload(s)
+}
+
+// CommitJob ... upload s on the main thread, blocking until it is done. Concurrent commits wait for the previous
+// upload to finish, Stage can be used to fill the other stage in the meantime.
+func (obj *RenderObject) CommitJob(s *VertexStage) {
+	obj.staging.commitLock.Lock()
+	defer obj.staging.commitLock.Unlock()
+
+	obj.staging.swap(s)
+
+	done := make(chan bool)
+
+	RenderObjectQueue <- RenderObjectJob{
+		obj,
+		[]interface{}{s, done},
+		nil,
+		callCommit,
+	}
+
+	<-done
+}
+
+func callCommit(job RenderObjectJob) {
+	job.obj.upload(job.params[0].(*VertexStage))
+	job.params[1].(chan bool) <- true
+}
+
+// upload ... replace the render object's vertices with the stage's in a single buffer update
+func (obj *RenderObject) upload(s *VertexStage) {
+	count := s.Len()
+
+	obj.vao.ClearVertices(0, obj.freeVert)
+	obj.vao.SetVertices(0, s.verts, obj.pixToTex(s.texs), s.colours)
+	obj.vao.UpdateBuffers()
+
+	// Every staged square is live so can be modified or removed by index
+	obj.replaceVertices(count, 6)
+}
+
+// AddSquare ... stage a square, arguments are as RenderObject.AddSquare
+func (s *VertexStage) AddSquare(x, y, xTex, yTex, width, widthTex float32) {
+	s.AddRect(x, y, xTex, yTex, width, width, widthTex, widthTex)
+}
+
+// AddRect ... stage a rectangle, arguments are as RenderObject.AddRect
+func (s *VertexStage) AddRect(x, y, xTex, yTex, width, height, widthTex, heightTex float32) {
+	if s.Len()+6 > s.obj.maxVert {
+		panic("Vertex stage overflow")
+	}
+
+	yTex, heightTex = s.obj.flipTexY(yTex, heightTex)
+
+	s.verts = append(s.verts,
+		// Upper right triangle
+		x, y,
+		x+width, y,
+		x+width, y+height,
+
+		// Lower left triangle
+		x, y,
+		x+width, y+height,
+		x, y+height,
+	)
+
+	s.texs = append(s.texs,
+		// Upper right triangle
+		xTex, yTex,
+		xTex+widthTex, yTex,
+		xTex+widthTex, yTex+heightTex,
+
+		// Lower left triangle
+		xTex, yTex,
+		xTex+widthTex, yTex+heightTex,
+		xTex, yTex+heightTex,
+	)
+
+	for i := 0; i < 6; i++ {
+		s.colours = append(s.colours, s.colour.R, s.colour.G, s.colour.B, s.colour.A)
+	}
+}
+
+// SetColor ... colour every vertex staged after this call, defaults to white
+func (s *VertexStage) SetColor(c Color) {
+	s.colour = c
+}
+
+// AddText ... stage text, arguments are as RenderObject.AddText with every glyph in the stage's colour
+func (s *VertexStage) AddText(font *Font, text string, x, y, scale float32) {
+	font.layout(text, x, y, scale, s.obj.yUp(), func(_ int, x, y, xTex, yTex float32) {
+		s.AddRect(x, y, xTex, yTex, font.glyphWidth*scale, font.glyphHeight*scale, font.glyphWidth, font.glyphHeight)
+	})
+}
+
+// Len ... number of staged vertices
+func (s *VertexStage) Len() int {
+	return len(s.verts) / opengl.DEFAULT_VECTOR_SIZE
+}
+
+// Reset ... remove all staged geometry and return to staging in white
+func (s *VertexStage) Reset() {
+	s.verts = s.verts[:0]
+	s.texs = s.texs[:0]
+	s.colours = s.colours[:0]
+	s.colour = White
+}
@@ -0,0 +1,99 @@
+package graphics
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Supersampling, the scene is rendered to an offscreen framebuffer at a multiple of the window's resolution and
+downsampled to the window when presented.
+*/
+
+type offscreenTarget struct {
+	fbo, colour, depthStencil uint32
+	width, height             int32
+}
+
+var (
+	renderScale float32 = 1
+	superTarget *offscreenTarget
+)
+
+// SetRenderScale ... render at scale times the window resolution, eg 2 renders 4 times as many pixels for high quality
+// anti-aliasing at a matching fill rate and memory cost. The size is clamped to the largest supported renderbuffer.
+// Line widths and point sizes are in framebuffer pixels so appear thinner when scaled. Defaults to 1, no supersampling.
+func SetRenderScale(scale float32) {
+	if scale <= 0 {
+		scale = 1
+	}
+
+	renderScale = scale
+}
+
+func renderSupersampled() {
+	fbWidth, fbHeight := window.GetFramebufferSize()
+
+	var maxSize int32
+	gl.GetIntegerv(gl.MAX_RENDERBUFFER_SIZE, &maxSize)
+
+	width := clampSize(int32(float32(fbWidth)*renderScale), maxSize)
+	height := clampSize(int32(float32(fbHeight)*renderScale), maxSize)
+
+	if superTarget == nil || superTarget.width != width || superTarget.height != height {
+		deleteSupersampling()
+		superTarget = createOffscreenTarget(width, height)
+	}
+
+	RenderToFBO(superTarget.fbo, int(width), int(height))
+
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, superTarget.fbo)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, 0)
+	gl.BlitFramebuffer(0, 0, width, height, 0, 0, int32(fbWidth), int32(fbHeight), gl.COLOR_BUFFER_BIT, gl.LINEAR)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+}
+
+func clampSize(size, max int32) int32 {
+	if size > max {
+		return max
+	}
+
+	if size < 1 {
+		return 1
+	}
+
+	return size
+}
+
+func createOffscreenTarget(width, height int32) *offscreenTarget {
+	target := &offscreenTarget{width: width, height: height}
+
+	gl.GenFramebuffers(1, &target.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target.fbo)
+
+	gl.GenRenderbuffers(1, &target.colour)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, target.colour)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.RGBA8, width, height)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, target.colour)
+
+	// Clipping and depth testing need a stencil and depth buffer as the window has
+	gl.GenRenderbuffers(1, &target.depthStencil)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, target.depthStencil)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, width, height)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, target.depthStencil)
+
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+
+	return target
+}
+
+func deleteSupersampling() {
+	if superTarget == nil {
+		return
+	}
+
+	gl.DeleteRenderbuffers(1, &superTarget.colour)
+	gl.DeleteRenderbuffers(1, &superTarget.depthStencil)
+	gl.DeleteFramebuffers(1, &superTarget.fbo)
+	superTarget = nil
+}
@@ -0,0 +1,50 @@
+package graphics
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+/*
+Test mode, time and input are injected instead of read from glfw so frames can be simulated deterministically
+without a window. Poll does not swap buffers or poll input while in test mode, injected keys still produce key events.
+*/
+
+var (
+	testMode  = false
+	testClock func() float64
+)
+
+func SetTestMode(enabled bool) {
+	testMode = enabled
+
+	if enabled {
+		if testClock != nil {
+			SetTimeSource(testClock)
+		}
+
+		return
+	}
+
+	SetTimeSource(nil)
+}
+
+// SetTestClock ... read time from fn instead of glfw, no-op outside of test mode
+func SetTestClock(fn func() float64) {
+	if !testMode {
+		return
+	}
+
+	testClock = fn
+	SetTimeSource(fn)
+}
+
+// InjectKey ... press or release a polled key, no-op outside of test mode
+func InjectKey(key glfw.Key, action glfw.Action) {
+	if !testMode {
+		return
+	}
+
+	if name, exists := keyNames[key]; exists {
+		KeyMap[name] = action != glfw.Release
+	}
+}
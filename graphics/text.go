@@ -0,0 +1,159 @@
+package graphics
+
+import (
+	"fmt"
+	"gopengl/graphics/opengl"
+)
+
+/*
+Bitmap fonts, glyphs are laid out in a grid on a single texture in rune order starting from the top left.
+Text can only be added to render objects created with the font's texture.
+*/
+
+type Font struct {
+	texture                 string
+	glyphWidth, glyphHeight float32
+	first                   rune
+	columns                 int
+}
+
+// CreateBitmapFont ... texture contains columns glyphs per row each glyphWidth by glyphHeight pixels, the first glyph is first
+func CreateBitmapFont(texture string, glyphWidth, glyphHeight float32, first rune, columns int) *Font {
+	return &Font{
+		texture,
+		glyphWidth,
+		glyphHeight,
+		first,
+		columns,
+	}
+}
+
+func (f *Font) Texture() string {
+	return f.texture
+}
+
+// AddText ... add text with its top left at x, y, every glyph is scaled by scale. colors holds one colour per rune,
+// if only one colour is given it is used for every rune and if none are given the text is white.
+// Returns the index of every glyph, newlines are skipped. Runes the font has no glyph for are drawn as '?', or left as
+// a gap if the font has no '?' either.
+func (obj *RenderObject) AddText(font *Font, text string, x, y, scale float32, colors ...Color) []int {
+	if obj.texture.File() != font.texture {
+		panic(fmt.Errorf("cannot add text using font %s to render object with texture %s", font.texture, obj.texture.File()))
+	}
+
+	runes := []rune(text)
+
+	if len(colors) > 1 && len(colors) != len(runes) {
+		panic(fmt.Errorf("text %q has %d runes but %d colours were given", text, len(runes), len(colors)))
+	}
+
+	glyphs := make([]int, 0, len(runes))
+
+	font.layout(text, x, y, scale, obj.yUp(), func(i int, x, y, xTex, yTex float32) {
+		index := obj.AddRect(x, y, xTex, yTex, font.glyphWidth*scale, font.glyphHeight*scale, font.glyphWidth, font.glyphHeight)
+		glyphs = append(glyphs, index)
+
+		switch len(colors) {
+		case 0:
+		case 1:
+			obj.SetColor(index, 6, colors[0])
+		default:
+			obj.SetColor(index, 6, colors[i])
+		}
+	})
+
+	return glyphs
+}
+
+// fallbackGlyph ... drawn in place of runes the font has no glyph for
+const fallbackGlyph = '?'
+
+// glyphCount ... the number of glyphs on the font's texture, -1 if the texture is not loaded so cannot be checked
+func (f *Font) glyphCount() int {
+	texture := opengl.FindTex(f.texture)
+
+	if texture == nil {
+		return -1
+	}
+
+	return f.columns * int(float32(texture.Height())/f.glyphHeight)
+}
+
+// hasGlyph ... whether r is within the font's texture, glyphs is from glyphCount
+func (f *Font) hasGlyph(r rune, glyphs int) bool {
+	glyph := int(r - f.first)
+
+	return glyph >= 0 && (glyphs < 0 || glyph < glyphs)
+}
+
+// layout ... call add with the rune index, position and texture position of every glyph in text, skipping newlines.
+// Runes outside of the font are replaced by fallbackGlyph, or skipped leaving a gap if the font does not have it.
+func (f *Font) layout(text string, x, y, scale float32, yUp bool, add func(i int, x, y, xTex, yTex float32)) {
+	width := f.glyphWidth * scale
+	height := f.glyphHeight * scale
+	cX, cY := x, y
+
+	// Lines advance down the window, with y up rects extend upwards from their y
+	advance := height
+
+	if yUp {
+		advance = -height
+		cY -= height
+	}
+
+	glyphs := f.glyphCount()
+
+	for i, r := range []rune(text) {
+		if r == '\n' {
+			cX = x
+			cY += advance
+
+			continue
+		}
+
+		if !f.hasGlyph(r, glyphs) {
+			r = fallbackGlyph
+		}
+
+		if !f.hasGlyph(r, glyphs) {
+			cX += width
+
+			continue
+		}
+
+		glyph := int(r - f.first)
+		xTex := float32(glyph%f.columns) * f.glyphWidth
+		yTex := float32(glyph/f.columns) * f.glyphHeight
+
+		add(i, cX, cY, xTex, yTex)
+
+		cX += width
+	}
+}
+
+/*
+Font registry, fonts can be registered by name so they do not need to be passed through UI code
+*/
+
+var fonts = make(map[string]*Font)
+
+// RegisterFont ... register f under name, replacing any font already registered with the name
+func RegisterFont(name string, f *Font) {
+	fonts[name] = f
+}
+
+// GetFont ... the font registered under name, nil if no font is registered with the name
+func GetFont(name string) *Font {
+	return fonts[name]
+}
+
+// AddTextNamed ... AddText using the font registered under fontName, returns an error if it is not registered
+func (obj *RenderObject) AddTextNamed(fontName string, text string, x, y, scale float32, colors ...Color) ([]int, error) {
+	font := GetFont(fontName)
+
+	if font == nil {
+		return nil, fmt.Errorf("font %s is not registered", fontName)
+	}
+
+	return obj.AddText(font, text, x, y, scale, colors...), nil
+}
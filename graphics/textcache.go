@@ -0,0 +1,97 @@
+package graphics
+
+import (
+	"fmt"
+	"gopengl/graphics/opengl"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+/*
+Text caching, static text is rendered once into a texture so it can be drawn as a single square.
+Textures are cached by font layout and text, rendering the same text again returns the cached texture.
+*/
+
+// RenderTextToTexture ... render text in white into a texture with a transparent background, returning the texture
+// and its width and height in pixels. Must be called on the main thread.
+func RenderTextToTexture(font *Font, text string) (*opengl.Texture, int, int) {
+	// Fonts sharing a texture can lay it out differently so the whole layout is part of the key
+	name := fmt.Sprintf("gopengl:text:%s:%v:%v:%d:%d:%s", font.texture, font.glyphWidth, font.glyphHeight, font.first,
+		font.columns, text)
+	lines := strings.Split(text, "\n")
+	columns := 0
+
+	for _, line := range lines {
+		if n := len([]rune(line)); n > columns {
+			columns = n
+		}
+	}
+
+	width := int(float32(columns) * font.glyphWidth)
+	height := int(float32(len(lines)) * font.glyphHeight)
+
+	if width == 0 || height == 0 {
+		panic(fmt.Errorf("cannot render empty text %q to a texture", text))
+	}
+
+	if cached := opengl.FindTex(name); cached != nil {
+		return cached, width, height
+	}
+
+	texture := opengl.CreateEmptyTexture(name, width, height)
+
+	glyphs := &RenderObject{}
+	createRenderObject(glyphs, len([]rune(text))*6, font.texture, true)
+	glyphs.screen = true
+	glyphs.SetBlendMode(BlendNone)
+	glyphs.AddText(font, text, 0, 0, 1)
+
+	withScreenSize(float32(width), float32(height), func() {
+		renderToTexture(texture, glyphs.Render)
+	})
+
+	glyphs.Delete()
+
+	return texture, width, height
+}
+
+// withScreenSize ... run fn with pixel coordinates mapped to a width by height target instead of the window.
+// Framebuffer rows start at the bottom while texture rows start at the top, so y is flipped to keep textures upright.
+func withScreenSize(width, height float32, fn func()) {
+	savedWidth, savedHeight := windowWidth, windowHeight
+	savedFlipX, savedFlipY := flipX, flipY
+
+	windowWidth, windowHeight = width, height
+	flipX, flipY = 1, -1
+	updateProjection()
+
+	fn()
+
+	windowWidth, windowHeight = savedWidth, savedHeight
+	flipX, flipY = savedFlipX, savedFlipY
+	updateProjection()
+}
+
+// renderToTexture ... run draw with texture cleared to transparent as the render target
+func renderToTexture(texture *opengl.Texture, draw func()) {
+	var fbo uint32
+	previousDraw, previousRead := framebufferBindings()
+	viewport := make([]int32, 4)
+
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	gl.GenFramebuffers(1, &fbo)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, fbo)
+	gl.FramebufferTexture2D(gl.DRAW_FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, texture.ID(), 0)
+	gl.Viewport(0, 0, int32(texture.Width()), int32(texture.Height()))
+
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	draw()
+
+	restoreFramebuffers(previousDraw, previousRead)
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	gl.DeleteFramebuffers(1, &fbo)
+}
@@ -0,0 +1,47 @@
+package graphics
+
+import (
+	"gopengl/graphics/opengl"
+)
+
+/*
+Texture cache budget, after each frame least recently used textures are deleted while the estimated texture memory
+exceeds the budget. Only textures loaded from a file are evicted, textures used by live render objects never are.
+*/
+
+var textureCacheBudget = 0
+
+// SetTextureCacheBudget ... limit the estimated gpu memory used by textures to bytes, 0 for no limit (default).
+// Only textures loaded from a file are evicted, they are reloaded by the next render object created with them.
+// Using an evicted texture directly, for instance through a sprite sheet, panics.
+func SetTextureCacheBudget(bytes int) {
+	textureCacheBudget = bytes
+}
+
+func evictTextures() {
+	if textureCacheBudget <= 0 {
+		return
+	}
+
+	opengl.EvictTextures(textureCacheBudget, referencedTextures())
+}
+
+// referencedTextures ... every texture used by a live render object, including internal ones
+func referencedTextures() map[*opengl.Texture]bool {
+	keep := make(map[*opengl.Texture]bool)
+	objs := append(RenderObjects(), debugTextObj, backgroundObj)
+
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+
+		keep[obj.texture] = true
+
+		if palette := obj.vao.Palette(); palette != nil {
+			keep[palette] = true
+		}
+	}
+
+	return keep
+}
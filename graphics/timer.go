@@ -0,0 +1,42 @@
+package graphics
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+/*
+Timing independent of the frame loop, by default time is read from glfw in seconds since it was initialized.
+*/
+
+var timeSource = glfw.GetTime
+
+// SetTimeSource ... replace the source of time used by Now and all timers, nil restores the glfw clock
+func SetTimeSource(fn func() float64) {
+	if fn == nil {
+		fn = glfw.GetTime
+	}
+
+	timeSource = fn
+}
+
+// Now ... current time in seconds
+func Now() float64 {
+	return timeSource()
+}
+
+type Timer struct {
+	start float64
+}
+
+func CreateTimer() *Timer {
+	return &Timer{Now()}
+}
+
+// Elapsed ... seconds since the timer was created or last reset
+func (t *Timer) Elapsed() float64 {
+	return Now() - t.start
+}
+
+func (t *Timer) Reset() {
+	t.start = Now()
+}
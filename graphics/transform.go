@@ -0,0 +1,61 @@
+package graphics
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+Repeated rendering, the same render object drawn several times in one frame with a different transform each time.
+Each transform is a separate draw call with its own uniform upload so this suits tens of copies, such as tiling or a
+formation of identical units. For thousands of copies true instancing is far cheaper.
+*/
+
+// Transform ... scale then rotation about the centre of the render object's bounds followed by a translation, all in pixels
+type Transform struct {
+	X, Y           float32
+	Rotation       float32 // Radians
+	ScaleX, ScaleY float32
+}
+
+// CreateTransform ... a transform translating by x, y without rotation or scaling
+func CreateTransform(x, y float32) Transform {
+	return Transform{x, y, 0, 1, 1}
+}
+
+// matrix ... the transform pivoting on cX, cY, vertices are in pixels so the pivot is moved to the origin and back
+func (t Transform) matrix(cX, cY float32) mgl32.Mat4 {
+	return mgl32.Translate3D(t.X+cX, t.Y+cY, 0).
+		Mul4(mgl32.HomogRotate3DZ(t.Rotation)).
+		Mul4(mgl32.Scale3D(t.ScaleX, t.ScaleY, 1)).
+		Mul4(mgl32.Translate3D(-cX, -cY, 0))
+}
+
+// RenderAt ... draw the render object once per transform, the transforms are applied on top of its own translation.
+// Requires the default shader.
+func (obj *RenderObject) RenderAt(transforms []Transform) {
+	if len(transforms) == 0 {
+		return
+	}
+
+	if obj.blend != BlendInherit {
+		applyBlendMode(obj.blend)
+	}
+
+	x, y, width, height := obj.Bounds()
+	cX, cY := x+width/2, y+height/2
+	vertNum := obj.PrepRender()
+
+	for _, t := range transforms {
+		obj.vao.SetModel(t.matrix(cX, cY))
+		gl.DrawArrays(obj.mode, 0, vertNum)
+		countDraw(vertNum)
+	}
+
+	obj.vao.SetModel(mgl32.Ident4())
+	obj.FinishRender()
+
+	if obj.blend != BlendInherit {
+		applyBlendMode(globalBlendMode)
+	}
+}
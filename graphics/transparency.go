@@ -0,0 +1,63 @@
+package graphics
+
+import (
+	"sort"
+)
+
+/*
+Transparency sorting, overlapping transparent render objects only blend correctly when drawn back to front.
+When enabled the default pass draws opaque objects front to back, then transparent objects back to front, by depth.
+Front to back only saves overdraw with depth testing, without it opaque objects are also drawn back to front so nearer
+objects are painted over farther ones. Objects of equal depth keep their draw order. Sorting every frame costs O(n log n) in the number of render objects.
+*/
+
+var transparencySort = false
+
+// SetTransparencySort ... sort render objects by depth in the default pass, overrides SetReverseDrawOrder when enabled
+func SetTransparencySort(enabled bool) {
+	transparencySort = enabled
+}
+
+// SetTransparent ... mark the render object as transparent for transparency sorting, eg when its texture has
+// translucent pixels. Objects with a colour mod alpha below 1 are always treated as transparent.
+func (obj *RenderObject) SetTransparent(transparent bool) {
+	obj.blended = transparent
+}
+
+func (obj *RenderObject) isTransparent() bool {
+	return obj.blended || obj.alpha < 1
+}
+
+func renderSortedPass() {
+	opaque := make([]*RenderObject, 0, len(renderObjects))
+	transparent := make([]*RenderObject, 0)
+
+	for _, obj := range renderObjects {
+		if obj.isTransparent() {
+			transparent = append(transparent, obj)
+		} else {
+			opaque = append(opaque, obj)
+		}
+	}
+
+	// Lower depths are in front
+	sort.SliceStable(opaque, func(i, j int) bool {
+		if depthTest {
+			return opaque[i].depth < opaque[j].depth
+		}
+
+		return opaque[i].depth > opaque[j].depth
+	})
+
+	sort.SliceStable(transparent, func(i, j int) bool {
+		return transparent[i].depth > transparent[j].depth
+	})
+
+	for _, obj := range opaque {
+		obj.Render()
+	}
+
+	for _, obj := range transparent {
+		obj.Render()
+	}
+}
@@ -1,34 +1,221 @@
 package graphics
 
 import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
 //TODO implement GLFWError
 func CreateWindow(width, height int, name string) *glfw.Window {
-	err := glfw.Init()
+	window, err := createWindow(Options{Width: width, Height: height, Title: name})
 
 	checkerr(err)
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	return window
+}
+
+// CreateWindowWithOptions ... create a window with settings CreateWindow leaves at their defaults, eg a resizable window
+func CreateWindowWithOptions(opts Options) (*glfw.Window, error) {
+	return createWindow(opts)
+}
+
+func createWindow(opts Options) (*glfw.Window, error) {
+	err := glfw.Init()
+
+	if err != nil {
+		return nil, err
+	}
+
+	glfw.WindowHint(glfw.Resizable, glfwBool(opts.Resizable))
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-	window, err := glfw.CreateWindow(width, height, name, nil, nil)
+	glfw.WindowHint(glfw.StencilBits, 8)
+	glfw.WindowHint(glfw.DoubleBuffer, glfwBool(doubleBuffered))
+	glfw.WindowHint(glfw.Samples, opts.Samples)
+	glfw.WindowHint(glfw.SRGBCapable, glfwBool(opts.SRGB))
+	window, err := glfw.CreateWindow(opts.Width, opts.Height, opts.Title, nil, nil)
 
-	checkerr(err)
+	if err != nil {
+		return nil, err
+	}
 
 	window.MakeContextCurrent()
 
-	return window
+	return window, nil
 }
 
 func Poll(window *glfw.Window) {
-	window.SwapBuffers()
+	// In test mode there may be no window, input is injected instead
+	if testMode {
+		updateKeyEvents()
+
+		return
+	}
+
+	applySwapInterval()
+
+	if doubleBuffered {
+		window.SwapBuffers()
+	} else {
+		gl.Flush()
+	}
+
 	pollInputs(window)
 }
 
+var doubleBuffered = true
+
+// SetDoubleBuffered ... choose between a double buffered (default) or single buffered window, single buffering draws
+// straight to the visible buffer for capture or low latency uses. This is a window hint so must be called before
+// CreateWindow, it cannot be changed for an existing window.
+func SetDoubleBuffered(enabled bool) {
+	doubleBuffered = enabled
+}
+
+func glfwBool(b bool) int {
+	if b {
+		return glfw.True
+	}
+
+	return glfw.False
+}
+
+/*
+Framebuffer resizing, minimizing a window can shrink its framebuffer to 0x0. Rendering and viewport updates are
+skipped until it has a positive size again.
+*/
+
+var framebufferEmpty = false
+
+func onFramebufferResize(_ *glfw.Window, width, height int) {
+	framebufferEmpty = width <= 0 || height <= 0
+
+	if framebufferEmpty {
+		return
+	}
+
+	gl.Viewport(0, 0, int32(width), int32(height))
+}
+
+/*
+Window size and aspect ratio constraints, these are applied to the current window immediately and kept for windows recreated by
+Reinitialize. Must be called on the main thread.
+*/
+
+var (
+	sizeLimits  = [4]int{glfw.DontCare, glfw.DontCare, glfw.DontCare, glfw.DontCare}
+	aspectRatio = [2]int{glfw.DontCare, glfw.DontCare}
+)
+
+// SetWindowSizeLimits ... prevent the window being resized outside of the given size in screen coordinates,
+// pass glfw.DontCare for an unbounded dimension. Only resizable windows, see Options.Resizable, can be resized. Panics if a minimum is greater than its maximum.
+func SetWindowSizeLimits(minW, minH, maxW, maxH int) {
+	if minW != glfw.DontCare && maxW != glfw.DontCare && minW > maxW {
+		panic(fmt.Errorf("minimum window width %d is greater than the maximum %d", minW, maxW))
+	}
+
+	if minH != glfw.DontCare && maxH != glfw.DontCare && minH > maxH {
+		panic(fmt.Errorf("minimum window height %d is greater than the maximum %d", minH, maxH))
+	}
+
+	sizeLimits = [4]int{minW, minH, maxW, maxH}
+	applySizeLimits()
+}
+
+func applySizeLimits() {
+	if window != nil {
+		window.SetSizeLimits(sizeLimits[0], sizeLimits[1], sizeLimits[2], sizeLimits[3])
+	}
+}
+
+// SetAspectRatioLock ... keep the window's width to height ratio at num:den while it is resized, eg 16, 9.
+// Only applies to resizable windows, see Options.Resizable.
+// Pass glfw.DontCare for both to unlock. Panics if only one is glfw.DontCare or either is not positive.
+func SetAspectRatioLock(num, den int) {
+	if (num == glfw.DontCare) != (den == glfw.DontCare) {
+		panic(fmt.Errorf("aspect ratio %d:%d must be fully locked or unlocked", num, den))
+	}
+
+	if num != glfw.DontCare && (num <= 0 || den <= 0) {
+		panic(fmt.Errorf("aspect ratio %d:%d must be positive", num, den))
+	}
+
+	aspectRatio = [2]int{num, den}
+	applyAspectRatio()
+}
+
+func applyAspectRatio() {
+	if window != nil {
+		window.SetAspectRatio(aspectRatio[0], aspectRatio[1])
+	}
+}
+
+/*
+VSync, these must be called after the window has been created and assigned.
+SetVSync can be called from any go routine, changes are applied on the main thread before the next buffer swap.
+Some drivers ignore runtime changes, notably when vsync is forced on or off in the driver's control panel.
+*/
+
+var (
+	swapInterval        int32 = 0 // Requested swap interval
+	appliedSwapInterval int32 = 0 // Swap interval last passed to glfw, only used on the main thread
+)
+
+// GetSwapInterval ... the requested swap interval, 0 without vsync, 1 with vsync and -1 with adaptive vsync
+func GetSwapInterval() int {
+	return int(atomic.LoadInt32(&swapInterval))
+}
+
+func SetVSync(enabled bool) {
+	if enabled {
+		setSwapInterval(1)
+
+		return
+	}
+
+	setSwapInterval(0)
+}
+
+// SetAdaptiveVSync ... use adaptive vsync where supported, tearing instead of stalling when the frame rate
+// drops below the refresh rate. Falls back to regular vsync, returns true if adaptive vsync was enabled.
+// Checking support requires the opengl context so this must be called on the main thread.
+func SetAdaptiveVSync(enabled bool) bool {
+	if !enabled {
+		SetVSync(false)
+
+		return false
+	}
+
+	if !glfw.ExtensionSupported("WGL_EXT_swap_control_tear") && !glfw.ExtensionSupported("GLX_EXT_swap_control_tear") {
+		SetVSync(true)
+
+		return false
+	}
+
+	setSwapInterval(-1)
+
+	return true
+}
+
+func setSwapInterval(interval int) {
+	atomic.StoreInt32(&swapInterval, int32(interval))
+}
+
+func applySwapInterval() {
+	interval := atomic.LoadInt32(&swapInterval)
+
+	if interval != appliedSwapInterval {
+		glfw.SwapInterval(int(interval))
+		appliedSwapInterval = interval
+	}
+}
+
 /*
 Input handling
 */
@@ -47,11 +234,32 @@ func pollInputs(window *glfw.Window) {
 	pollMouse(window)
 }
 
+// Polled keys and their names in KeyMap
+var keyNames = map[glfw.Key]string{
+	glfw.KeyW:      "w",
+	glfw.KeyA:      "a",
+	glfw.KeyS:      "s",
+	glfw.KeyD:      "d",
+	glfw.KeyUp:     "up",
+	glfw.KeyDown:   "down",
+	glfw.KeyLeft:   "left",
+	glfw.KeyRight:  "right",
+	glfw.KeyEnter:  "enter",
+	glfw.KeyEscape: "escape",
+	glfw.KeySpace:  "space",
+}
+
+// SetPolledKey ... poll key each call to Poll, reporting it in KeyMap as name. Must be called on the main thread.
+func SetPolledKey(key glfw.Key, name string) {
+	keyNames[key] = name
+}
+
 func pollKeys(window *glfw.Window) {
-	KeyMap["w"] = window.GetKey(glfw.KeyW) == glfw.Press
-	KeyMap["a"] = window.GetKey(glfw.KeyA) == glfw.Press
-	KeyMap["s"] = window.GetKey(glfw.KeyS) == glfw.Press
-	KeyMap["d"] = window.GetKey(glfw.KeyD) == glfw.Press
+	for key, name := range keyNames {
+		KeyMap[name] = window.GetKey(key) == glfw.Press
+	}
+
+	updateKeyEvents()
 }
 
 func Key(key string) bool {
@@ -68,6 +276,136 @@ func KeyComboPressed(keys []string) bool {
 	return true
 }
 
+/*
+Key events, a key is just pressed on the first poll it is held for. Keys are grouped into named actions, eg "menu down"
+bound to "s" and "down", and actions opted in to repeating are also just pressed again at a fixed cadence while any of
+their keys is held, independent of the os key repeat rate, for consistent menu navigation.
+*/
+
+type action struct {
+	keys      []string
+	repeat    bool
+	held      bool    // Whether any of the keys was held on the last poll
+	pressed   bool    // Just pressed on the last poll, including repeats
+	scheduled bool    // Whether next is the time of the next repeat
+	next      float64 // Time of the next synthetic press while held
+}
+
+var (
+	previousKeys   = map[string]bool{}
+	justPressed    = map[string]bool{}
+	actions        = map[string]*action{}
+	repeatDelay    = 0.5
+	repeatInterval = 0.1
+)
+
+// JustPressed ... true on the poll key was pressed, keys never repeat, bind them to an action to repeat them
+func JustPressed(key string) bool {
+	return justPressed[key]
+}
+
+// BindAction ... trigger the action name with any of keys, replacing its previous keys but keeping its repeat setting.
+// Panics if a key is not polled, see SetPolledKey.
+func BindAction(name string, keys ...string) {
+	for _, key := range keys {
+		if !isPolledKey(key) {
+			panic(fmt.Errorf("cannot bind action %q to unpolled key %q", name, key))
+		}
+	}
+
+	if a, exists := actions[name]; exists {
+		a.keys = keys
+
+		return
+	}
+
+	actions[name] = &action{keys: keys}
+}
+
+// ActionJustPressed ... true on the poll one of the action's keys was pressed while none were held, and at the repeat
+// cadence while held if repeating is enabled for the action
+func ActionJustPressed(name string) bool {
+	a, exists := actions[name]
+
+	return exists && a.pressed
+}
+
+// SetKeyRepeat ... held repeating actions are first repeated after initialDelay then every interval. Defaults to 500ms and 100ms
+func SetKeyRepeat(initialDelay, interval time.Duration) {
+	if interval <= 0 {
+		panic(fmt.Errorf("key repeat interval must be positive, got %v", interval))
+	}
+
+	repeatDelay = initialDelay.Seconds()
+	repeatInterval = interval.Seconds()
+}
+
+// SetActionRepeat ... opt the action in to or out of repeating, actions do not repeat by default. An action enabled
+// while held first repeats initialDelay after the next poll. Panics if the action has not been bound.
+func SetActionRepeat(name string, enabled bool) {
+	a, exists := actions[name]
+
+	if !exists {
+		panic(fmt.Errorf("cannot set repeating of unbound action %q", name))
+	}
+
+	a.repeat = enabled
+	a.scheduled = false
+}
+
+func isPolledKey(key string) bool {
+	for _, name := range keyNames {
+		if name == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func updateKeyEvents() {
+	for name, pressed := range KeyMap {
+		justPressed[name] = pressed && !previousKeys[name]
+		previousKeys[name] = pressed
+	}
+
+	now := Now()
+
+	for _, a := range actions {
+		held := false
+
+		for _, key := range a.keys {
+			held = held || KeyMap[key]
+		}
+
+		a.pressed = held && !a.held
+		a.held = held
+
+		if !held || !a.repeat {
+			a.scheduled = false
+
+			continue
+		}
+
+		// Actions enabled for repeating while already held are scheduled from now
+		if a.pressed || !a.scheduled {
+			a.next = now + repeatDelay
+			a.scheduled = true
+
+			continue
+		}
+
+		if now >= a.next {
+			a.pressed = true
+
+			// Repeats missed by a slow frame are dropped rather than all reported at once
+			for a.next <= now {
+				a.next += repeatInterval
+			}
+		}
+	}
+}
+
 func pollMouse(window *glfw.Window) {
 	RButton = window.GetMouseButton(glfw.MouseButtonRight) == glfw.Press
 	LButton = window.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press
@@ -0,0 +1,36 @@
+package graphics
+
+import (
+	"math"
+	"testing"
+)
+
+func isFinite(v float32) bool {
+	return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+}
+
+func TestZeroSizeFramebuffer(t *testing.T) {
+	// A zero size never reaches gl.Viewport so no context is needed
+	onFramebufferResize(nil, 0, 0)
+	defer func() { framebufferEmpty = false }()
+
+	if !framebufferEmpty {
+		t.Fatalf("framebuffer is not empty after resizing to 0x0")
+	}
+
+	for _, o := range origins {
+		withOrigin(o.origin, func() {
+			windowWidth, windowHeight = 0, 0
+
+			if x, y := NormVert(10, 10); !isFinite(x) || !isFinite(y) {
+				t.Errorf("%s: NormVert(10, 10) = %v, %v on a zero size window", o.name, x, y)
+			}
+
+			for i, v := range PixToScreen([]float32{10, 10, -10, 5}) {
+				if !isFinite(v) {
+					t.Errorf("%s: PixToScreen coordinate %d = %v on a zero size window", o.name, i, v)
+				}
+			}
+		})
+	}
+}
@@ -1,8 +1,37 @@
 #version 410
 uniform sampler2D tex;
+//Palette lookup, when paletted is 1 the red channel of tex indexes the first row of palette
+uniform sampler2D palette;
+uniform float paletted;
+//Colour modulation, texel*colourmul+colouradd
+uniform vec4 colourmul;
+uniform vec4 colouradd;
+//Fragments with an alpha below the threshold are discarded
+uniform float alphathreshold;
+//Solid fill colour, used in place of the texel colour when filled is 1
+uniform vec4 fill;
+uniform float filled;
 
 out vec4 frag_colour;
 in vec2 fragtexcoord;
+in vec4 fragcolour;
 void main(){
-    frag_colour=texture(tex, fragtexcoord);
+    vec4 texel;
+    if(fragtexcoord.x<-.5){
+        //Smooth circle, texture coordinates offset by -2 hold the position within the circle from -1 to 1
+        float dist=length(fragtexcoord+2.);
+        float feather=fwidth(dist);
+        texel=vec4(1.,1.,1.,1.-smoothstep(1.-feather,1.,dist));
+    }else{
+        texel=texture(tex, fragtexcoord);
+        if(paletted>.5){
+            texel=texelFetch(palette,ivec2(int(texel.r*255.+.5),0),0);
+        }
+    }
+    texel*=fragcolour;
+    texel=texel*colourmul+colouradd;
+    frag_colour=mix(texel,vec4(fill.rgb,fill.a*texel.a),filled);
+    if(frag_colour.a<alphathreshold){
+        discard;
+    }
 }
\ No newline at end of file
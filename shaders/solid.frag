@@ -0,0 +1,7 @@
+#version 410
+uniform vec4 colour;
+
+out vec4 frag_colour;
+void main(){
+    frag_colour=colour;
+}
\ No newline at end of file
@@ -0,0 +1,15 @@
+#version 410
+in vec2 vert;
+
+//Window dimension scaling
+uniform vec2 dim;
+
+void main(){
+    vec2 pos=vert;
+    
+    // Apply screen scaling from pixel coordinates
+    pos.x=(pos.x/(.5*dim.x))-1;
+    pos.y=1-(pos.y/(.5*dim.y));
+    
+    gl_Position=vec4(pos,0.,1.);
+}
\ No newline at end of file
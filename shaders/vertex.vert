@@ -2,18 +2,33 @@
 in vec2 vert;
 in vec4 rotgroup;
 in vec2 verttexcoord;
+in vec4 vertcolour;
 
 //Translation, window dimension scaling, rotation
 uniform vec2 trans;
 uniform vec2 dim;
 uniform vec4 rot;
 
+//Projection from pixel coordinates and depth within the projection's depth range
+uniform mat4 proj;
+uniform float depth;
+//Model transform applied in pixel coordinates before projection, identity unless drawn with RenderAt
+uniform mat4 model;
+
+//Scaling about a centre, x,y centre z,w scale
+uniform vec4 scale;
+
 out vec2 fragtexcoord;
+out vec4 fragcolour;
 void main(){
     // Set tex coords for frag shader
     fragtexcoord=verttexcoord;
+    fragcolour=vertcolour;
     vec2 pos=vert;
     
+    //Apply scaling before any rotation
+    pos=(pos-scale.xy)*scale.zw+scale.xy;
+    
     //Apply rotgroup rotation first, we want local changes then global changes to each vertex
     vec2 rotcenter=vec2(rotgroup.x,rotgroup.y);
     pos-=rotcenter;
@@ -39,9 +54,6 @@ void main(){
     
     pos=pos+rotcenter;
     
-    // Apply screen scaling from pixel coordinates
-    pos.x=(pos.x/(.5*dim.x))-1;
-    pos.y=1-(pos.y/(.5*dim.y));
-    
-    gl_Position=vec4(pos+trans,0.,1.);
+    // Apply projection from pixel coordinates
+    gl_Position=proj*model*vec4(pos,-depth,1.)+vec4(trans,0.,0.);
 }
\ No newline at end of file